@@ -10,18 +10,16 @@ func ConstructRoot() *layout.RootLayout {
 
 	constructHeader(root)
 	constructMain(root)
-	// constructFooter(root)
+	constructFooter(root)
+	root.SetStylesheet(constructStylesheet())
 
 	return root
 }
 
 func constructHeader(root *layout.RootLayout) {
-	header := layout.NewTextView("bitwave")
-	headerStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("13")).
-		Padding(0, 1)
-	root.AddStatic(header, 1, headerStyle, 0)
+	header := layout.NewTextView("[b][fg=title]bitwave[/fg][/b]")
+	header.SetID("header")
+	root.AddStatic(header, 1, lipgloss.NewStyle(), 0)
 }
 
 func constructMain(root *layout.RootLayout) {
@@ -34,13 +32,15 @@ func constructMain(root *layout.RootLayout) {
 	// main.Add(box, 1, lipgloss.NewStyle(), 1)
 
 	// Left column - Todo List
-	// todoList := layout.NewListLayout("Todo List", 0)
-	// todoList.AddItems([]string{
-	// 	"Review PRs",
-	// 	"Write docs",
-	// 	"Fix bug #123",
-	// })
-	// main.Add(todoList, 1, lipgloss.NewStyle().Border(lipgloss.RoundedBorder()), 0)
+	todoList := layout.NewListLayout("Todo List", 0)
+	todoList.SetID("todos")
+	todoList.SetEditable(true)
+	todoList.AddItems([]string{
+		"Review PRs",
+		"Write docs",
+		"Fix bug #123",
+	})
+	main.Add(todoList, 1, lipgloss.NewStyle().Border(lipgloss.RoundedBorder()), 0)
 
 	// Center column - Table with add/delete
 	// userTable := layout.NewTableLayout([]string{"Name", "Role"}, false)
@@ -59,9 +59,13 @@ func constructMain(root *layout.RootLayout) {
 }
 
 func constructFooter(root *layout.RootLayout) {
-	footer := layout.NewTextView("Tab/Shift+Tab: Navigate | Enter: Focus/Edit | Esc: Exit | Ctrl+C: Quit")
-	footerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Padding(0, 1)
-	root.AddStatic(footer, 1, footerStyle, 0)
+	footer := layout.NewHelpBar()
+	root.AddStatic(footer, 1, lipgloss.NewStyle(), 0)
+}
+
+func constructStylesheet() *layout.Stylesheet {
+	sheet := layout.NewStylesheet()
+	sheet.MustAdd("#header", lipgloss.NewStyle().Padding(0, 1))
+	sheet.MustAdd("HelpBar", lipgloss.NewStyle().Padding(0, 1))
+	return sheet
 }
@@ -1,10 +1,239 @@
 package bindings
 
+// Global actions, handled by layouts while navigating
+const (
+	CycleFocusForward  Action = "cycle_focus_forward"
+	CycleFocusBackward Action = "cycle_focus_backward"
+	CycleEnter         Action = "cycle_enter"
+	CycleEscape        Action = "cycle_escape"
+
+	QuitProgram Action = "quit_program"
+	Help        Action = "help"
+	Palette     Action = "palette"
+
+	// Leader is the key "<leader>" stands for in sequences
+	Leader Action = "leader"
+)
+
+// Component actions, shared between the component keymaps
+const (
+	Up     Action = "up"
+	Down   Action = "down"
+	Left   Action = "left"
+	Right  Action = "right"
+	Top    Action = "top"
+	Bottom Action = "bottom"
+
+	PageUp   Action = "page_up"
+	PageDown Action = "page_down"
+
+	Toggle     Action = "toggle"
+	SelectAll  Action = "select_all"
+	SelectNone Action = "select_none"
+	Filter     Action = "filter"
+
+	ExtendUp        Action = "extend_up"
+	ExtendDown      Action = "extend_down"
+	Visual          Action = "visual"
+	InvertSelection Action = "invert_selection"
+	SelectMatching  Action = "select_matching"
+
+	InsertItem Action = "insert_item"
+	RenameItem Action = "rename_item"
+	DeleteItem Action = "delete_item"
+	Undo       Action = "undo"
+	MoveUp     Action = "move_up"
+	MoveDown   Action = "move_down"
+
+	Edit      Action = "edit"
+	Save      Action = "save"
+	Cancel    Action = "cancel"
+	AddRow    Action = "add_row"
+	DeleteRow Action = "delete_row"
+
+	Activate Action = "activate"
+	Submit   Action = "submit"
+	Indent   Action = "indent"
+
+	Run Action = "run"
+
+	Follow        Action = "follow"
+	Search        Action = "search"
+	NextMatch     Action = "next_match"
+	PreviousMatch Action = "previous_match"
+	CycleLevel    Action = "cycle_level"
+
+	HalfPageUp        Action = "half_page_up"
+	HalfPageDown      Action = "half_page_down"
+	SearchBackward    Action = "search_backward"
+	GoToLine          Action = "go_to_line"
+	ToggleLineNumbers Action = "toggle_line_numbers"
+	ToggleWrap        Action = "toggle_wrap"
+)
+
+// Default keymaps
+// Components clone these when they're constructed, so overrides must be
+// applied (e.g. with LoadOverrides) before building the layout tree
 var (
-	CycleFocusForward = "tab"
-	CycleFocusBackward = "shift+tab"
-	CycleEnter = "enter"
-	CycleEscape = "esc"
+	Global = NewKeymap("global").
+		Bind(CycleFocusForward, "next", "tab", "ctrl+w l").
+		Bind(CycleFocusBackward, "previous", "shift+tab", "ctrl+w h").
+		Bind(CycleEnter, "enter", "enter").
+		Bind(CycleEscape, "back", "esc").
+		Bind(QuitProgram, "quit", "ctrl+c").
+		Bind(Help, "help", "?", "<leader> h").
+		Bind(Palette, "command palette", "ctrl+p", "<leader> p").
+		// Nothing else binds the leader, so keys components use, like space
+		// toggling list items, never wait for the sequence timeout
+		Bind(Leader, "leader", "\\")
+
+	List = NewKeymap("list").
+		Bind(Up, "up", "up", "k").
+		Bind(Down, "down", "down", "j").
+		Bind(Top, "go to top", "g g", "home").
+		Bind(Bottom, "go to bottom", "G", "end").
+		Bind(Toggle, "toggle selection", " ").
+		Bind(Activate, "open", "enter").
+		Bind(SelectAll, "select all", "a").
+		Bind(SelectNone, "select none", "A").
+		Bind(Filter, "filter", "/").
+		Bind(ExtendUp, "extend selection up", "shift+up", "K").
+		Bind(ExtendDown, "extend selection down", "shift+down", "J").
+		Bind(Visual, "visual mode", "v").
+		Bind(InvertSelection, "invert selection", "i").
+		Bind(SelectMatching, "select matching", "*").
+		Bind(InsertItem, "insert item", "o").
+		Bind(RenameItem, "rename item", "r").
+		Bind(DeleteItem, "delete item", "d").
+		Bind(Undo, "undo", "u").
+		Bind(MoveUp, "move item up", "alt+k", "alt+up").
+		Bind(MoveDown, "move item down", "alt+j", "alt+down")
 
-	QuitProgram = "ctrl+c"
+	// ListEdit is active while an item of an editable list is being typed
+	ListEdit = NewKeymap("list_edit").
+			Bind(Save, "save", "enter").
+			Bind(Cancel, "cancel", "esc")
+
+	// ListVisual is active in a list's visual mode, where moving the cursor
+	// selects everything between it and where visual mode started
+	ListVisual = NewKeymap("list_visual").
+			Bind(Up, "extend up", "up", "k").
+			Bind(Down, "extend down", "down", "j").
+			Bind(Top, "extend to top", "g g", "home").
+			Bind(Bottom, "extend to bottom", "G", "end").
+			Bind(Visual, "keep selection", "v", "enter").
+			Bind(Cancel, "cancel", "esc")
+
+	// ListPattern is active while typing a pattern to select matching items
+	ListPattern = NewKeymap("list_pattern").
+			Bind(Run, "select matching", "enter").
+			Bind(Cancel, "cancel", "esc")
+
+	// ListFilter is active while typing a list filter
+	ListFilter = NewKeymap("list_filter").
+			Bind(Up, "previous", "up", "ctrl+k").
+			Bind(Down, "next", "down", "ctrl+j").
+			Bind(Run, "apply filter", "enter").
+			Bind(Cancel, "clear filter", "esc")
+
+	Table = NewKeymap("table").
+		Bind(Up, "up", "up", "k").
+		Bind(Down, "down", "down", "j").
+		Bind(Left, "left", "left", "h").
+		Bind(Right, "right", "right", "l").
+		Bind(Edit, "edit cell", "enter", "e", " ").
+		Bind(AddRow, "add row", "n").
+		Bind(DeleteRow, "delete row", "d")
+
+	// TableEdit is active while a table cell is being edited
+	TableEdit = NewKeymap("table_edit").
+			Bind(Save, "save", "enter").
+			Bind(Cancel, "cancel", "esc")
+
+	Textarea = NewKeymap("textarea").
+			Bind(Activate, "start editing", "enter").
+			Bind(Cancel, "stop editing", "esc").
+			Bind(Submit, "submit", "ctrl+s").
+			Bind(Indent, "indent", "tab")
+
+	// Text is active on scrollable text
+	Text = NewKeymap("text").
+		Bind(Up, "scroll up", "up", "k").
+		Bind(Down, "scroll down", "down", "j").
+		Bind(Left, "scroll left", "left", "h").
+		Bind(Right, "scroll right", "right", "l").
+		Bind(PageUp, "page up", "pgup", "ctrl+u").
+		Bind(PageDown, "page down", "pgdown", "ctrl+d").
+		Bind(Top, "go to top", "g g", "home").
+		Bind(Bottom, "go to bottom", "G", "end")
+
+	// Log is active on log views
+	Log = NewKeymap("log").
+		Bind(Up, "scroll up", "up", "k").
+		Bind(Down, "scroll down", "down", "j").
+		Bind(PageUp, "page up", "pgup", "ctrl+u").
+		Bind(PageDown, "page down", "pgdown", "ctrl+d").
+		Bind(Top, "go to top", "g g", "home").
+		Bind(Bottom, "go to bottom", "G", "end").
+		Bind(Follow, "toggle follow", "f").
+		Bind(Search, "search", "/").
+		Bind(NextMatch, "next match", "n").
+		Bind(PreviousMatch, "previous match", "N").
+		Bind(CycleLevel, "cycle level filter", "L")
+
+	// LogSearch is active while typing a log search
+	LogSearch = NewKeymap("log_search").
+			Bind(Run, "search", "enter").
+			Bind(Cancel, "cancel", "esc")
+
+	// Pager is active on pagers, with less-like keys
+	Pager = NewKeymap("pager").
+		Bind(Up, "line up", "up", "k", "y").
+		Bind(Down, "line down", "down", "j", "e").
+		Bind(Left, "scroll left", "left", "h").
+		Bind(Right, "scroll right", "right", "l").
+		Bind(HalfPageUp, "half page up", "u", "ctrl+u").
+		Bind(HalfPageDown, "half page down", "d", "ctrl+d").
+		Bind(PageUp, "page up", "b", "pgup").
+		Bind(PageDown, "page down", " ", "f", "pgdown").
+		Bind(Top, "go to top", "g g", "home", "<").
+		Bind(Bottom, "go to bottom", "G", "end", ">").
+		Bind(Search, "search forward", "/").
+		Bind(SearchBackward, "search backward", "?").
+		Bind(NextMatch, "next match", "n").
+		Bind(PreviousMatch, "previous match", "N").
+		Bind(GoToLine, "go to line", ":").
+		Bind(ToggleLineNumbers, "toggle line numbers", "#").
+		Bind(ToggleWrap, "toggle wrap", "w")
+
+	// PagerPrompt is active while typing a pager search or line number
+	PagerPrompt = NewKeymap("pager_prompt").
+			Bind(Run, "go", "enter").
+			Bind(Cancel, "cancel", "esc")
+
+	// CommandPalette is active while the command palette is open
+	CommandPalette = NewKeymap("command_palette").
+			Bind(Up, "previous", "up", "ctrl+k").
+			Bind(Down, "next", "down", "ctrl+j").
+			Bind(Run, "run", "enter").
+			Bind(Cancel, "close", "esc")
 )
+
+var keymaps = map[string]*Keymap{}
+
+func init() {
+	for _, k := range []*Keymap{Global, List, ListFilter, ListVisual, ListPattern, ListEdit, Table, TableEdit, Textarea, Text, Log, LogSearch, Pager, PagerPrompt, CommandPalette} {
+		Register(k)
+	}
+}
+
+// Register makes a keymap available to LoadOverrides under its name
+func Register(k *Keymap) {
+	keymaps[k.name] = k
+}
+
+// Lookup returns the registered keymap with the given name
+func Lookup(name string) (*Keymap, bool) {
+	k, ok := keymaps[name]
+	return k, ok
+}
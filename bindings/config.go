@@ -0,0 +1,117 @@
+package bindings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Overrides is the on-disk format for key overrides:
+// keymap name -> action -> keys, e.g. {"list": {"up": ["k", "up"]}}
+type Overrides map[string]map[Action][]string
+
+// DefaultConfigPath is where user key overrides are read from at startup
+func DefaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bitwave", "keys.json"), nil
+}
+
+// LoadOverrides reads overrides from a JSON file and applies them
+// A missing file is not an error
+func LoadOverrides(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var overrides Overrides
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if err := Apply(overrides); err != nil {
+		return fmt.Errorf("applying %s: %w", path, err)
+	}
+	return nil
+}
+
+// Apply applies overrides to the registered keymaps
+// It fails on unknown keymaps or actions, on keys that end up bound to two
+// actions in the same keymap, and on keys an override newly shares with
+// Global, which would never reach the component since Global keys go first
+// Nothing is changed unless every override applies
+func Apply(overrides Overrides) error {
+	updated := map[string]*Keymap{}
+	for name, actions := range overrides {
+		keymap, ok := Lookup(name)
+		if !ok {
+			return fmt.Errorf("unknown keymap %q", name)
+		}
+		clone := keymap.Clone()
+		for action, keys := range actions {
+			if err := clone.Override(action, keys...); err != nil {
+				return err
+			}
+		}
+		if conflicts := clone.Conflicts(); len(conflicts) > 0 {
+			return conflictError(conflicts)
+		}
+		updated[name] = clone
+	}
+
+	global, ok := updated[Global.name]
+	if !ok {
+		global = Global
+	}
+	conflicts := []Conflict{}
+	for name, keymap := range keymaps {
+		if keymap == Global {
+			continue
+		}
+		next, ok := updated[name]
+		if !ok && global == Global {
+			continue
+		}
+		if !ok {
+			next = keymap
+		}
+
+		// Defaults like enter and esc are shared on purpose, so only
+		// conflicts the overrides added count
+		before := map[Conflict]bool{}
+		for _, c := range keymap.ConflictsWith(Global) {
+			before[c] = true
+		}
+		for _, c := range next.ConflictsWith(global) {
+			if !before[c] {
+				conflicts = append(conflicts, c)
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return conflictError(conflicts)
+	}
+
+	for name, clone := range updated {
+		*keymaps[name] = *clone
+	}
+	return nil
+}
+
+func conflictError(conflicts []Conflict) error {
+	messages := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		messages[i] = c.String()
+	}
+	sort.Strings(messages)
+	return errors.New(strings.Join(messages, "; "))
+}
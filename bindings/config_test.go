@@ -0,0 +1,53 @@
+package bindings
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides Overrides
+		wantErr   string // Substring of the error, "" for none
+	}{
+		{"valid", Overrides{"list": {Up: {"w"}}}, ""},
+		{"unknown keymap", Overrides{"nope": {Up: {"w"}}}, "unknown keymap"},
+		{"unknown action", Overrides{"list": {Up: {"w"}, "nope": {"x"}}}, "no action"},
+		{"conflict within keymap", Overrides{"list": {Up: {"j"}}}, "list.down"},
+		{"conflict with global", Overrides{"list": {Up: {"tab"}}}, "global.cycle_focus_forward"},
+		{"global conflicts with a keymap", Overrides{"global": {Help: {"j"}}}, "global.help"},
+		{"shared defaults are fine", Overrides{"list": {Up: {"up"}}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := map[string]Keymap{}
+			for name, keymap := range keymaps {
+				saved[name] = *keymap.Clone()
+			}
+			defer func() {
+				for name, keymap := range saved {
+					*keymaps[name] = keymap
+				}
+			}()
+
+			err := Apply(tt.overrides)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Apply: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Apply error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+			// A failed Apply leaves every keymap as it was
+			for name, keymap := range keymaps {
+				if !reflect.DeepEqual(*keymap, saved[name]) {
+					t.Errorf("keymap %s changed by a failed Apply", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package bindings
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Action names something a key can trigger
+type Action string
+
+// Binding is the set of keys bound to an action, plus a short description
+type Binding struct {
+	Keys []string
+	Help string
+}
+
+// Keymap maps named actions to keys
+// Actions keep the order they were bound in, which is the order help lists them
+type Keymap struct {
+	name     string
+	order    []Action
+	bindings map[Action]Binding
+}
+
+// Conflict is a key bound to two different actions
+type Conflict struct {
+	Key     string
+	Keymap  string
+	Action  Action
+	Other   string // Keymap of the other action
+	Against Action
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%q is bound to both %s.%s and %s.%s", c.Key, c.Keymap, c.Action, c.Other, c.Against)
+}
+
+func NewKeymap(name string) *Keymap {
+	return &Keymap{
+		name:     name,
+		bindings: map[Action]Binding{},
+	}
+}
+
+func (k *Keymap) Name() string {
+	return k.name
+}
+
+// Bind sets the keys and help for action, adding it if it's new
+func (k *Keymap) Bind(action Action, help string, keys ...string) *Keymap {
+	if _, ok := k.bindings[action]; !ok {
+		k.order = append(k.order, action)
+	}
+	k.bindings[action] = Binding{Keys: normalizeKeys(keys), Help: help}
+	return k
+}
+
+// Override replaces the keys of an existing action, keeping its help
+func (k *Keymap) Override(action Action, keys ...string) error {
+	binding, ok := k.bindings[action]
+	if !ok {
+		return fmt.Errorf("keymap %s has no action %q", k.name, action)
+	}
+	binding.Keys = normalizeKeys(keys)
+	k.bindings[action] = binding
+	return nil
+}
+
+// Keys returns the keys bound to action
+func (k *Keymap) Keys(action Action) []string {
+	return k.bindings[action].Keys
+}
+
+// Binding returns the binding for action
+func (k *Keymap) Binding(action Action) (Binding, bool) {
+	binding, ok := k.bindings[action]
+	return binding, ok
+}
+
+// Actions returns every action in the order it was bound
+func (k *Keymap) Actions() []Action {
+	return append([]Action(nil), k.order...)
+}
+
+// ActionMsg triggers an action directly, as if one of its keys was pressed
+type ActionMsg struct {
+	Action Action
+}
+
+// Matches reports whether msg triggers action
+// msg is a tea.KeyMsg for single keys, a SequenceMsg for sequences,
+// or an ActionMsg naming the action
+func (k *Keymap) Matches(msg tea.Msg, action Action) bool {
+	var pressed []string
+	switch m := msg.(type) {
+	case tea.KeyMsg:
+		pressed = []string{m.String()}
+	case SequenceMsg:
+		pressed = m.Keys
+	case ActionMsg:
+		_, bound := k.bindings[action]
+		return bound && m.Action == action
+	default:
+		return false
+	}
+
+	for _, bound := range k.bindings[action].Keys {
+		if equalSteps(Steps(bound), pressed) {
+			return true
+		}
+	}
+	return false
+}
+
+// ActionFor returns the first action msg triggers
+func (k *Keymap) ActionFor(msg tea.Msg) (Action, bool) {
+	for _, action := range k.order {
+		if k.Matches(msg, action) {
+			return action, true
+		}
+	}
+	return "", false
+}
+
+// Sequences returns every multi-key sequence bound in the keymap
+func (k *Keymap) Sequences() []Sequence {
+	sequences := []Sequence{}
+	for _, action := range k.order {
+		binding := k.bindings[action]
+		for _, bound := range binding.Keys {
+			if steps := Steps(bound); len(steps) > 1 {
+				sequences = append(sequences, Sequence{Steps: steps, Action: action, Help: binding.Help})
+			}
+		}
+	}
+	return sequences
+}
+
+// Clone returns an independent copy, for per-component overrides
+func (k *Keymap) Clone() *Keymap {
+	clone := NewKeymap(k.name)
+	for _, action := range k.order {
+		binding := k.bindings[action]
+		clone.Bind(action, binding.Help, binding.Keys...)
+	}
+	return clone
+}
+
+// Conflicts returns keys bound to more than one action in this keymap
+func (k *Keymap) Conflicts() []Conflict {
+	conflicts := []Conflict{}
+	seen := map[string]Action{}
+	for _, action := range k.order {
+		for _, key := range k.bindings[action].Keys {
+			if other, ok := seen[key]; ok {
+				conflicts = append(conflicts, Conflict{
+					Key: key, Keymap: k.name, Action: action, Other: k.name, Against: other,
+				})
+				continue
+			}
+			seen[key] = action
+		}
+	}
+	return conflicts
+}
+
+// ConflictsWith returns keys bound both in this keymap and in other
+func (k *Keymap) ConflictsWith(other *Keymap) []Conflict {
+	conflicts := []Conflict{}
+	for _, action := range k.order {
+		for _, key := range k.bindings[action].Keys {
+			for _, otherAction := range other.order {
+				for _, otherKey := range other.bindings[otherAction].Keys {
+					if key == otherKey {
+						conflicts = append(conflicts, Conflict{
+							Key: key, Keymap: k.name, Action: action, Other: other.name, Against: otherAction,
+						})
+					}
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// DisplayKey returns a human readable form of key
+func DisplayKey(key string) string {
+	if steps := strings.Fields(key); len(steps) > 1 {
+		for i, step := range steps {
+			steps[i] = DisplayKey(step)
+		}
+		return strings.Join(steps, " ")
+	}
+
+	switch key {
+	case leaderPlaceholder:
+		return DisplayKey(LeaderKey())
+	case " ", "space":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	}
+	return key
+}
+
+// normalizeKeys maps config spellings onto the names tea.KeyMsg.String uses
+func normalizeKeys(keys []string) []string {
+	normalized := make([]string, len(keys))
+	for i, key := range keys {
+		normalized[i] = normalizeKey(key)
+	}
+	return normalized
+}
+
+func normalizeKey(key string) string {
+	trimmed := strings.TrimSpace(key)
+	if trimmed == "" || strings.EqualFold(trimmed, "space") {
+		return " "
+	}
+
+	// Sequences are normalized step by step, spelling space out
+	// so it doesn't get confused with the separator
+	if fields := strings.Fields(trimmed); len(fields) > 1 {
+		for i, field := range fields {
+			if fields[i] = normalizeKey(field); fields[i] == " " {
+				fields[i] = "space"
+			}
+		}
+		return strings.Join(fields, " ")
+	}
+
+	// Single characters keep their case: "G" and "g" are different keys
+	if len([]rune(trimmed)) == 1 || trimmed == leaderPlaceholder {
+		return trimmed
+	}
+	return strings.ToLower(trimmed)
+}
@@ -0,0 +1,69 @@
+package bindings
+
+import (
+	"strings"
+)
+
+// leaderPlaceholder stands for the leader key inside a sequence, e.g. "<leader> f"
+const leaderPlaceholder = "<leader>"
+
+// SequenceMsg is dispatched when a multi-key sequence such as "g g" completes
+type SequenceMsg struct {
+	Keys []string
+}
+
+func (s SequenceMsg) String() string {
+	return strings.Join(s.Keys, " ")
+}
+
+// Sequence is a multi-key sequence bound to an action
+type Sequence struct {
+	Steps  []string
+	Action Action
+	Help   string
+}
+
+// HasPrefix reports whether pressed is the start of the sequence
+func (s Sequence) HasPrefix(pressed []string) bool {
+	return len(pressed) <= len(s.Steps) && equalSteps(s.Steps[:len(pressed)], pressed)
+}
+
+// Steps splits a bound key into the individual keys of its sequence,
+// expanding <leader> to the key bound to the Leader action
+// A plain key is a sequence of one step
+func Steps(bound string) []string {
+	if bound == " " {
+		return []string{" "}
+	}
+
+	steps := strings.Fields(bound)
+	for i, step := range steps {
+		switch step {
+		case "space":
+			steps[i] = " "
+		case leaderPlaceholder:
+			steps[i] = LeaderKey()
+		}
+	}
+	return steps
+}
+
+// LeaderKey returns the key sequences use for <leader>
+func LeaderKey() string {
+	if keys := Global.Keys(Leader); len(keys) > 0 {
+		return keys[0]
+	}
+	return "\\"
+}
+
+func equalSteps(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
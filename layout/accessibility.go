@@ -0,0 +1,165 @@
+package layout
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// Accessibility switches on rendering for terminals and users that can't
+// rely on color
+type Accessibility struct {
+	// Markers adds text to cues that are otherwise only a color, like the
+	// selected table cell, and draws focus with borders rather than color
+	Markers bool
+	// Linear replaces the layout with a plain-text outline of every
+	// component, headed by the focused one, for screen readers
+	Linear bool
+}
+
+var accessibility Accessibility
+
+// DetectAccessibility turns on markers when the terminal has no colors,
+// including when NO_COLOR is set
+func DetectAccessibility() Accessibility {
+	return Accessibility{
+		Markers: lipgloss.ColorProfile() == termenv.Ascii,
+	}
+}
+
+// SetAccessibility changes the accessible rendering modes
+func (r *RootLayout) SetAccessibility(a Accessibility) {
+	accessibility = a
+	r.restyle()
+}
+
+// Describer is implemented by models that can summarize their state in a
+// sentence, e.g. "Todo list, item 2 of 5: Write docs"
+type Describer interface {
+	Describe() string
+}
+
+// describe summarizes model for the linear view
+func describe(model SizedModel) string {
+	description := typeLabel(model)
+	if describer, ok := model.(Describer); ok {
+		description = describer.Describe()
+	}
+	if ident, ok := model.(Identifiable); ok && ident.ID() != "" {
+		description += " #" + ident.ID()
+	}
+	if isDisabled(model) {
+		description += " (disabled)"
+	}
+	return description
+}
+
+// typeName returns the Go type name of model, e.g. "ListLayout"
+func typeName(model SizedModel) string {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	name := t.Name()
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i] // Generic types
+	}
+	return name
+}
+
+// typeLabel turns a type name like "TextareaLayout" into "Textarea"
+func typeLabel(model SizedModel) string {
+	name := strings.TrimSuffix(typeName(model), "Layout")
+	if name == "Generic" || name == "" {
+		return "Layout"
+	}
+	return name
+}
+
+// marked wraps content in open and close in place of its style's
+// horizontal padding, so the cell keeps its width
+func marked(style lipgloss.Style, content, open, close string) string {
+	return style.PaddingLeft(0).PaddingRight(0).Render(open + content + close)
+}
+
+// focusLeaf returns the innermost focused component
+func focusLeaf() SizedModel {
+	path := focusPath()
+	if len(path) == 0 {
+		return nil
+	}
+	return path[len(path)-1]
+}
+
+// trackFocus remembers the focused component so the linear view can
+// announce when it changes
+func (r *RootLayout) trackFocus() {
+	leaf := focusLeaf()
+	r.focusMoved = leaf != r.lastFocus
+	r.lastFocus = leaf
+}
+
+// linearView renders every component as plain text, one after the other
+func (r *RootLayout) linearView() string {
+	lines := []string{}
+
+	if leaf := focusLeaf(); leaf != nil {
+		if r.focusMoved {
+			lines = append(lines, "Focus moved to "+describe(leaf))
+		} else {
+			lines = append(lines, "Focus: "+describe(leaf))
+		}
+	}
+
+	// Overlays come first, since they have the user's attention
+	width := r.inner.width
+	switch {
+	case r.palette.open:
+		lines = append(lines, "", "Command palette", unboxed(r.palette.View(width)))
+	case r.showHelp:
+		lines = append(lines, "", "Help", unboxed(helpView()))
+	case len(r.sequence.pending) > 0:
+		lines = append(lines, "", "Pending keys", unboxed(r.whichKeyView()))
+	}
+
+	leaf := focusLeaf()
+	walk(r.inner, func(model SizedModel) {
+		if _, ok := model.(*GenericLayout); ok {
+			return
+		}
+		heading := "== " + describe(model)
+		if model == leaf {
+			heading += " (focused)"
+		}
+		lines = append(lines, "", heading+" ==")
+		if text := plainText(model.View()); text != "" {
+			lines = append(lines, text)
+		}
+	})
+
+	return strings.Join(lines, "\n")
+}
+
+// plainText strips styling and the blank space around a view
+func plainText(view string) string {
+	lines := strings.Split(ansi.Strip(view), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// unboxed returns the plain text of an overlay without its border
+func unboxed(view string) string {
+	lines := []string{}
+	for _, line := range strings.Split(plainText(view), "\n") {
+		line = strings.Trim(line, "│╭╮╰╯─ ")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,67 @@
+package layout
+
+import (
+	"strings"
+	"testing"
+)
+
+// activeLayout is a component that reports when it's editing
+type activeLayout interface {
+	SizedModel
+	IsActive() bool
+}
+
+func TestDescribeLeavesStateToUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		build  func() (activeLayout, func())
+		start  []string // Keys that start an edit
+		before string   // Expected in the description before the source changes
+	}{
+		{
+			name: "list",
+			build: func() (activeLayout, func()) {
+				source := NewObservableList("a", "b", "c")
+				l := NewListLayout("Names", 0)
+				l.SetEditable(true)
+				l.BindItems(source)
+				return l, func() { source.Replace([]string{"z"}) }
+			},
+			start:  []string{"G", "r"},
+			before: "item 3 of 3: c",
+		},
+		{
+			name: "table",
+			build: func() (activeLayout, func()) {
+				source := NewObservableList([]string{"a"}, []string{"b"}, []string{"c"})
+				table := NewTableLayout([]string{"name"}, false)
+				table.BindRows(source)
+				return table, func() { source.Replace([][]string{{"z"}}) }
+			},
+			start:  []string{"down", "down", "enter"},
+			before: "row 3 of 3, name: c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, shrink := tt.build()
+			model.SetSize(40, 10)
+			press(model, tt.start...)
+			shrink()
+
+			// Rendering describes what the last Update saw
+			if got := describe(model); !strings.Contains(got, tt.before) {
+				t.Errorf("describe = %q, want it to contain %q", got, tt.before)
+			}
+			model.View()
+			if !model.IsActive() {
+				t.Fatal("rendering cancelled the edit")
+			}
+
+			model.Update(ObservableChangedMsg{})
+			if model.IsActive() {
+				t.Error("still editing after Update synced the source")
+			}
+		})
+	}
+}
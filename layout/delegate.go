@@ -0,0 +1,151 @@
+package layout
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ItemDelegate draws the items of a ListLayout
+// Items can take any number of lines; the list scrolls by whole items
+type ItemDelegate interface {
+	// Height returns how many lines item takes at width
+	Height(item ListItem, width int) int
+	// Render draws item in Height lines of at most state.Width cells
+	Render(item ListItem, state ItemState) string
+}
+
+// ItemState is what a delegate knows about the item it's drawing
+type ItemState struct {
+	Index      int // Index of the item in the list, ignoring the filter
+	Width      int
+	Cursor     bool
+	Selected   bool
+	Matches    []int // Rune indices of Value that match the filter
+	Styles     ItemStyles
+	Truncation Truncation // The list's truncation, for text that doesn't fit
+}
+
+// ItemStyles are the list's themed styles, so delegates follow the theme
+type ItemStyles struct {
+	Normal         lipgloss.Style
+	Cursor         lipgloss.Style
+	Selected       lipgloss.Style
+	SelectedCursor lipgloss.Style
+	Match          lipgloss.Style // Characters matching the filter
+	Muted          lipgloss.Style // Secondary text like descriptions
+}
+
+// Style returns the style for the item's cursor and selection state
+func (s ItemState) Style() lipgloss.Style {
+	switch {
+	case s.Cursor && s.Selected:
+		return s.Styles.SelectedCursor
+	case s.Cursor:
+		return s.Styles.Cursor
+	case s.Selected:
+		return s.Styles.Selected
+	}
+	return s.Styles.Normal
+}
+
+// Marker returns the cursor and selection marks in front of an item,
+// which also carry the state without color
+func (s ItemState) Marker() string {
+	switch {
+	case s.Cursor && s.Selected:
+		return "▶ ✓ "
+	case s.Cursor:
+		return "▶ "
+	case s.Selected:
+		return "  ✓ "
+	}
+	return "    "
+}
+
+// Value renders the item's value with filter matches highlighted, cut to width
+// Matches are styled run by run on top of style, so style carries on after each one
+func (s ItemState) Value(item ListItem, style lipgloss.Style, width int) string {
+	text := highlightMatches(item.Value, s.Matches, style.UnsetPadding(), s.Styles.Match)
+	return s.Truncation.Truncate(text, width)
+}
+
+// DefaultDelegate draws each item on one line behind its marker
+type DefaultDelegate struct{}
+
+func (DefaultDelegate) Height(ListItem, int) int {
+	return 1
+}
+
+func (DefaultDelegate) Render(item ListItem, state ItemState) string {
+	style := state.Style()
+	prefix := state.Marker()
+
+	// Width includes the style's padding
+	maxWidth := state.Width - textWidth(prefix) - style.GetHorizontalFrameSize()
+	return style.Width(state.Width).Render(prefix + state.Value(item, style, maxWidth))
+}
+
+// DetailDelegate draws an item as a title line with an optional icon and
+// badge, and a muted description line below it
+// Each func is optional and returning "" leaves that part out
+type DetailDelegate struct {
+	Icon        func(item ListItem) string
+	Badge       func(item ListItem) string // Right-aligned on the title line
+	Description func(item ListItem) string
+	Spacing     int // Blank lines after each item
+}
+
+func (d DetailDelegate) part(fn func(ListItem) string, item ListItem) string {
+	if fn == nil {
+		return ""
+	}
+	return fn(item)
+}
+
+func (d DetailDelegate) Height(item ListItem, width int) int {
+	height := 1 + d.Spacing
+	if d.part(d.Description, item) != "" {
+		height++
+	}
+	return height
+}
+
+func (d DetailDelegate) Render(item ListItem, state ItemState) string {
+	// Same padding and marker width in every state, so the columns line up
+	style := state.Style().Padding(0, 1)
+	prefix := padRight(state.Marker(), 4)
+	inner := state.Width - style.GetHorizontalFrameSize()
+
+	// Title line: marker, icon, value and badge
+	head := prefix
+	if icon := d.part(d.Icon, item); icon != "" {
+		head += icon + " "
+	}
+	badge := d.part(d.Badge, item)
+	if badge != "" {
+		badge = " " + badge
+	}
+	room := max(inner-textWidth(head)-textWidth(badge), 0)
+	value := state.Value(item, style, room)
+	gap := strings.Repeat(" ", max(inner-textWidth(head)-textWidth(value)-textWidth(badge), 0))
+	lines := []string{style.Width(state.Width).Render(head + value + gap + badge)}
+
+	// Description lines up under the value
+	if description := d.part(d.Description, item); description != "" {
+		indent := strings.Repeat(" ", textWidth(head))
+		description = state.Truncation.Truncate(description, inner-len(indent))
+		descStyle := state.Styles.Muted.
+			Padding(0, 0, 0, style.GetPaddingLeft()).
+			Width(state.Width)
+		if state.Cursor {
+			descStyle = descStyle.Background(style.GetBackground())
+		}
+		lines = append(lines, descStyle.Render(indent+description))
+	}
+
+	for range d.Spacing {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
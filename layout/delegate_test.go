@@ -0,0 +1,80 @@
+package layout
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetailDelegateScroll(t *testing.T) {
+	items := itemValues(20)
+	describe := func(item ListItem) string { return "about " + item.Value }
+	tests := []struct {
+		name     string
+		delegate ItemDelegate
+		height   int
+		items    []string
+		filter   string
+		keys     []string
+		want     string // Value under the cursor, "" for none
+	}{
+		{"one line items", DefaultDelegate{}, 8, items, "", []string{"G", "k"}, "item 18"},
+		{"description", DetailDelegate{Description: describe}, 10, items, "", []string{"j", "j", "j", "j", "j"}, "item 05"},
+		{"description and spacing", DetailDelegate{Description: describe, Spacing: 1}, 10, items, "", []string{"G"}, "item 19"},
+		{"back up", DetailDelegate{Description: describe, Spacing: 1}, 10, items, "", []string{"G", "k", "k", "k", "k", "k"}, "item 14"},
+		{"taller than the room", DetailDelegate{Description: describe, Spacing: 2}, 3, items, "", []string{"j", "j"}, "item 02"},
+		{"filtered", DetailDelegate{Description: describe}, 10, items, "15", []string{"j", "G"}, "item 15"},
+		{"filter matching nothing", DetailDelegate{Description: describe}, 10, items, "zz", []string{"j", "G"}, ""},
+		{"empty", DetailDelegate{Description: describe, Spacing: 1}, 10, nil, "", []string{"j", "G", "k"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewListLayout("Items", 0)
+			l.SetDelegate(tt.delegate)
+			l.SetSize(30, tt.height)
+			l.AddItems(tt.items)
+			l.SetFilter(tt.filter)
+			press(l, tt.keys...)
+
+			shown := l.shown()
+			pos := l.cursorPosition(shown)
+			if tt.want == "" {
+				if pos >= 0 {
+					t.Errorf("cursor on %q, want none", l.items[l.cursor].Value)
+				}
+			} else if pos < 0 || l.items[l.cursor].Value != tt.want {
+				t.Fatalf("cursor at %d, want %q", pos, tt.want)
+			}
+			if l.scrollOffset < 0 || (pos >= 0 && l.scrollOffset > pos) {
+				t.Errorf("scrollOffset %d with the cursor at %d", l.scrollOffset, pos)
+			}
+
+			view := l.View()
+			if tt.want != "" && !strings.Contains(view, tt.want) {
+				t.Errorf("cursor's item %q not on screen:\n%s", tt.want, view)
+			}
+		})
+	}
+}
+
+func TestDetailDelegateShrinkingSource(t *testing.T) {
+	values := itemValues(30)
+	source := NewObservableList(values...)
+	l := NewListLayout("Items", 0)
+	l.SetDelegate(DetailDelegate{
+		Description: func(item ListItem) string { return "about " + item.Value },
+		Spacing:     1,
+	})
+	l.SetSize(30, 10)
+	l.BindItems(source)
+	shrinkWhile(l, source, []string{"G"}, values[:3]...)
+	press(l, "k")
+
+	shown := l.shown()
+	pos := l.cursorPosition(shown)
+	if pos < 0 || l.scrollOffset > pos {
+		t.Fatalf("scrollOffset %d with the cursor at %d of %d", l.scrollOffset, pos, len(shown))
+	}
+	if view := l.View(); !strings.Contains(view, l.items[l.cursor].Value) {
+		t.Errorf("cursor's item %q not on screen:\n%s", l.items[l.cursor].Value, view)
+	}
+}
@@ -0,0 +1,94 @@
+package layout
+
+import (
+	"github.com/cactircool/bitwave/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Disableable is implemented by models that can be switched off at runtime
+type Disableable interface {
+	SetDisabled(disabled bool)
+	IsDisabled() bool
+}
+
+// DisabledState lets a model be switched off at runtime
+// Embed it in a model to make it Disableable, and report Disabled from
+// GetFocusState while it's set
+type DisabledState struct {
+	disabled bool
+}
+
+func (d *DisabledState) SetDisabled(disabled bool) {
+	d.disabled = disabled
+}
+
+func (d *DisabledState) IsDisabled() bool {
+	return d.disabled
+}
+
+// DisabledMsg switches the component it's sent to on or off
+type DisabledMsg struct {
+	Disabled bool
+}
+
+// Disable returns a command that switches off the component with the given ID
+func Disable(id string) tea.Cmd {
+	return SendTo(id, DisabledMsg{Disabled: true})
+}
+
+// Enable returns a command that switches the component with the given ID back on
+func Enable(id string) tea.Cmd {
+	return SendTo(id, DisabledMsg{Disabled: false})
+}
+
+func isDisabled(model SizedModel) bool {
+	d, ok := model.(Disableable)
+	return ok && d.IsDisabled()
+}
+
+// canFocus reports whether a model in state can take focus right now
+func canFocus(state FocusState) bool {
+	return state == Focusable || state == Interactive
+}
+
+// renderDisabled dims a disabled component's view
+// The view's own colors are dropped so nothing stands out
+func renderDisabled(view string) string {
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Color(theme.Muted)).
+		Faint(true).
+		Render(ansi.Strip(view))
+}
+
+// releaseDisabled moves focus off children that were disabled while focused
+func (l *GenericLayout) releaseDisabled() tea.Cmd {
+	var cmd tea.Cmd
+	if l.focused >= 0 && l.focused < len(l.children) && isDisabled(l.children[l.focused].model) {
+		child := &l.children[l.focused]
+		if childLayout, ok := child.model.(*GenericLayout); ok {
+			dropFocus(childLayout)
+		}
+
+		previous := l.focused
+		if globalFocusStack.Contains(l) {
+			cmd = l.cycleForward()
+		}
+		if l.focused == previous {
+			// Nothing else to focus
+			child.model.OnBlur()
+			child.currentStyle = child.baseStyle
+			l.focused = -1
+			l.layoutChildren()
+		}
+	}
+
+	cmds := []tea.Cmd{cmd}
+	for _, child := range l.children {
+		if childLayout, ok := child.model.(*GenericLayout); ok {
+			cmds = append(cmds, childLayout.releaseDisabled())
+		}
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,114 @@
+package layout
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// EventBus is an in-process publish/subscribe bus owned by the RootLayout
+// Components publish events with Publish, and anything holding the bus can
+// subscribe to an event type with Subscribe
+type EventBus struct {
+	subs []*Subscription
+}
+
+// Subscription is a single handler registered on an EventBus
+type Subscription struct {
+	bus    *EventBus
+	owner  SizedModel
+	handle func(event any) tea.Cmd
+}
+
+// eventMsg carries a published event up to the RootLayout
+type eventMsg struct {
+	event any
+}
+
+// Events published by the built-in components
+
+// SelectionChangedEvent is published when a ListLayout's selection changes
+type SelectionChangedEvent struct {
+	Source   *ListLayout
+	Selected []ListItem
+}
+
+// CellEditedEvent is published when a TableLayout cell is saved
+// Row is -1 when a header was edited
+type CellEditedEvent struct {
+	Source   *TableLayout
+	Row      int
+	Col      int
+	OldValue string
+	Value    string
+}
+
+// TextSubmittedEvent is published when a TextareaLayout submits its text
+type TextSubmittedEvent struct {
+	Source *TextareaLayout
+	Text   string
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Publish returns a command that delivers event to every subscriber of its type
+func Publish(event any) tea.Cmd {
+	return func() tea.Msg {
+		return eventMsg{event: event}
+	}
+}
+
+// Subscribe registers handler for events of type E
+// If owner is non-nil the subscription is dropped when owner is removed from the tree
+func Subscribe[E any](bus *EventBus, owner SizedModel, handler func(E) tea.Cmd) *Subscription {
+	sub := &Subscription{
+		bus:   bus,
+		owner: owner,
+		handle: func(event any) tea.Cmd {
+			if e, ok := event.(E); ok {
+				return handler(e)
+			}
+			return nil
+		},
+	}
+	bus.subs = append(bus.subs, sub)
+	return sub
+}
+
+// Unsubscribe removes the subscription from its bus
+func (s *Subscription) Unsubscribe() {
+	if s.bus == nil {
+		return
+	}
+	for i, sub := range s.bus.subs {
+		if sub == s {
+			s.bus.subs = append(s.bus.subs[:i], s.bus.subs[i+1:]...)
+			break
+		}
+	}
+	s.bus = nil
+}
+
+// dispatch runs every handler subscribed to the event's type
+func (b *EventBus) dispatch(event any) tea.Cmd {
+	// Copy so handlers can unsubscribe while we iterate
+	subs := make([]*Subscription, len(b.subs))
+	copy(subs, b.subs)
+
+	cmds := make([]tea.Cmd, 0, len(subs))
+	for _, sub := range subs {
+		cmds = append(cmds, sub.handle(event))
+	}
+	return tea.Batch(cmds...)
+}
+
+// unsubscribeOwner drops every subscription owned by model or anything below it
+func (b *EventBus) unsubscribeOwner(model SizedModel) {
+	walk(model, func(m SizedModel) {
+		for _, sub := range append([]*Subscription(nil), b.subs...) {
+			if sub.owner == m {
+				sub.Unsubscribe()
+			}
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package layout
+
+import (
+	"github.com/cactircool/bitwave/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FocusIndicator is how a component on the focus path is drawn
+type FocusIndicator int
+
+const (
+	// FocusThickBorder swaps the border for a thick one
+	FocusThickBorder FocusIndicator = iota
+	// FocusBorderColor recolors the border
+	FocusBorderColor
+	// FocusBackground tints the background
+	FocusBackground
+	// FocusTitle highlights the component's title, or recolors the border
+	// of components without one
+	FocusTitle
+	// FocusNone draws nothing
+	FocusNone
+)
+
+// FocusPolicy decides how focus is shown along the focus path
+type FocusPolicy struct {
+	// Leaf is the focused component of the innermost layout
+	Leaf FocusIndicator
+	// Ancestor is every layout that has been entered on the way there
+	Ancestor FocusIndicator
+}
+
+// DefaultFocusPolicy draws the leaf with a thick border and recolors the
+// borders of the layouts around it
+var DefaultFocusPolicy = FocusPolicy{
+	Leaf:     FocusThickBorder,
+	Ancestor: FocusBorderColor,
+}
+
+var focusPolicy = DefaultFocusPolicy
+
+// Titled is implemented by models with a title the FocusTitle indicator
+// can highlight
+type Titled interface {
+	SetTitleHighlighted(highlighted bool)
+}
+
+// SetFocusPolicy changes how focus is shown and restyles the tree
+func (r *RootLayout) SetFocusPolicy(policy FocusPolicy) {
+	focusPolicy = policy
+	r.restyle()
+}
+
+// usesBorder reports whether indicator draws a border on model
+func (indicator FocusIndicator) usesBorder(model SizedModel) bool {
+	if accessibility.Markers {
+		return indicator != FocusNone
+	}
+	switch indicator {
+	case FocusThickBorder, FocusBorderColor:
+		return true
+	case FocusTitle:
+		_, titled := model.(Titled)
+		return !titled
+	}
+	return false
+}
+
+// resting returns the unfocused style of a child
+// Borderless children that a border indicator could apply to get an
+// invisible border, so focusing them doesn't shift the layout
+func (p FocusPolicy) resting(style lipgloss.Style, model SizedModel) lipgloss.Style {
+	if hasBorder(style) || model.GetFocusState() == NotFocusable {
+		return style
+	}
+	_, isLayout := model.(*GenericLayout)
+	if p.Leaf.usesBorder(model) || isLayout && p.Ancestor.usesBorder(model) {
+		return style.Border(lipgloss.HiddenBorder())
+	}
+	return style
+}
+
+// apply returns style with the indicator for a leaf or an ancestor on top
+func (p FocusPolicy) apply(style lipgloss.Style, model SizedModel, ancestor bool) lipgloss.Style {
+	indicator, color := p.Leaf, leafColor()
+	if ancestor {
+		indicator, color = p.Ancestor, ancestorColor()
+	}
+	if accessibility.Markers && indicator != FocusNone {
+		// Without color, the border's shape is what shows focus
+		indicator = FocusThickBorder
+	}
+
+	switch indicator {
+	case FocusThickBorder:
+		if hiddenOrNone(style) {
+			style = style.Border(lipgloss.ThickBorder())
+		} else {
+			style = style.BorderStyle(lipgloss.ThickBorder())
+		}
+		if _, none := color.(lipgloss.NoColor); !none {
+			style = style.BorderForeground(color)
+		}
+
+	case FocusBorderColor:
+		style = borderColor(style, color)
+
+	case FocusBackground:
+		if ancestor {
+			style = style.Background(color)
+		} else {
+			style = style.Background(theme.Current().Color(theme.Highlight))
+		}
+
+	case FocusTitle:
+		if _, titled := model.(Titled); !titled {
+			style = borderColor(style, color)
+		}
+	}
+	return style
+}
+
+// highlightsTitle reports whether the child in the given focus role should
+// have its title highlighted
+func (p FocusPolicy) highlightsTitle(focused, ancestor bool) bool {
+	if !focused || accessibility.Markers {
+		return false
+	}
+	if ancestor {
+		return p.Ancestor == FocusTitle
+	}
+	return p.Leaf == FocusTitle
+}
+
+func borderColor(style lipgloss.Style, color lipgloss.TerminalColor) lipgloss.Style {
+	if hiddenOrNone(style) {
+		style = style.Border(lipgloss.RoundedBorder())
+	}
+	if _, none := color.(lipgloss.NoColor); none {
+		color = theme.Current().Color(theme.Primary)
+	}
+	return style.BorderForeground(color)
+}
+
+// leafColor is the theme's focus color, if it sets one
+func leafColor() lipgloss.TerminalColor {
+	return theme.Current().Color(theme.FocusBorder)
+}
+
+// ancestorColor is the color of layouts on the focus path
+func ancestorColor() lipgloss.TerminalColor {
+	t := theme.Current()
+	if t.Has(theme.FocusPath) {
+		return t.Color(theme.FocusPath)
+	}
+	return t.Color(theme.Muted)
+}
+
+func hasBorder(style lipgloss.Style) bool {
+	return style.GetHorizontalBorderSize() > 0 || style.GetVerticalBorderSize() > 0
+}
+
+func hiddenOrNone(style lipgloss.Style) bool {
+	return !hasBorder(style) || style.GetBorderStyle() == lipgloss.HiddenBorder()
+}
@@ -0,0 +1,140 @@
+package layout
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fuzzyMatch reports whether every rune of pattern appears in text in order,
+// ignoring case
+// positions are the rune indices of text that matched; higher scores mean
+// tighter matches (consecutive runs, word starts, early matches)
+func fuzzyMatch(pattern, text string) (score int, positions []int, ok bool) {
+	if pattern == "" {
+		return 0, nil, true
+	}
+
+	needle := []rune(strings.ToLower(pattern))
+	haystack := []rune(text)
+	positions = make([]int, 0, len(needle))
+
+	n := 0
+	prev := -2
+	for i, r := range haystack {
+		if n == len(needle) {
+			break
+		}
+		if unicode.ToLower(r) != needle[n] {
+			continue
+		}
+
+		score++
+		if i == prev+1 {
+			score += 5 // Consecutive run
+		}
+		if i == 0 || !unicode.IsLetter(haystack[i-1]) && !unicode.IsDigit(haystack[i-1]) {
+			score += 3 // Start of a word
+		}
+		positions = append(positions, i)
+		prev = i
+		n++
+	}
+
+	if n < len(needle) {
+		return 0, nil, false
+	}
+
+	// Prefer matches that start early
+	score -= positions[0] / 4
+	return score, positions, true
+}
+
+// fuzzyResult is one candidate that matched a fuzzy filter
+type fuzzyResult struct {
+	index     int // Index into the candidates
+	score     int
+	positions []int
+}
+
+// fuzzyFilter returns the candidates matching pattern, best first
+// Ties keep the candidates' original order
+func fuzzyFilter(pattern string, candidates []string) []fuzzyResult {
+	results := []fuzzyResult{}
+	for i, candidate := range candidates {
+		if score, positions, ok := fuzzyMatch(pattern, candidate); ok {
+			results = append(results, fuzzyResult{index: i, score: score, positions: positions})
+		}
+	}
+	if pattern != "" {
+		sort.SliceStable(results, func(a, b int) bool {
+			return results[a].score > results[b].score
+		})
+	}
+	return results
+}
+
+// highlightMatches renders text with base, and the runes at positions with match on top
+func highlightMatches(text string, positions []int, base, match lipgloss.Style) string {
+	if len(positions) == 0 {
+		return base.Render(text)
+	}
+
+	matched := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		matched[p] = true
+	}
+	highlight := match.Inherit(base)
+
+	var b strings.Builder
+	var run []rune
+	inMatch := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if inMatch {
+			b.WriteString(highlight.Render(string(run)))
+		} else {
+			b.WriteString(base.Render(string(run)))
+		}
+		run = run[:0]
+	}
+
+	for i, r := range []rune(text) {
+		if matched[i] != inMatch {
+			flush()
+			inMatch = matched[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+
+	return b.String()
+}
+
+// substringPositions returns the rune indices of text covered by
+// non-overlapping occurrences of query, for highlightMatches
+func substringPositions(text, query string, ignoreCase bool) []int {
+	if query == "" {
+		return nil
+	}
+	if ignoreCase {
+		text, query = strings.ToLower(text), strings.ToLower(query)
+	}
+	haystack, needle := []rune(text), []rune(query)
+	positions := []int{}
+	for i := 0; i+len(needle) <= len(haystack); {
+		if string(haystack[i:i+len(needle)]) == query {
+			for k := range needle {
+				positions = append(positions, i+k)
+			}
+			i += len(needle)
+		} else {
+			i++
+		}
+	}
+	return positions
+}
@@ -9,11 +9,15 @@ import (
 )
 
 type GenericLayout struct {
+	Identity
+	DisabledState
 	direction Direction
 	children  []LayoutChild
 	width     int
 	height    int
 	focused   int
+
+	stylesheet *Stylesheet // Optional rules for this subtree
 }
 
 type Direction int
@@ -36,7 +40,9 @@ type LayoutChild struct {
 	weight       float64
 	size         int
 	gap          int
+	inlineStyle  lipgloss.Style // Style passed to Add, applied over any stylesheet
 	baseStyle    lipgloss.Style // Original style
+	focusStyle   lipgloss.Style // Style the model asked for in OnFocus
 	currentStyle lipgloss.Style // Current style (may include focus styling)
 }
 
@@ -53,6 +59,7 @@ func (l *GenericLayout) Add(model SizedModel, weight float64, style lipgloss.Sty
 		sizeMode:     Weighted,
 		weight:       weight,
 		gap:          gap,
+		inlineStyle:  style,
 		baseStyle:    style,
 		currentStyle: style,
 	})
@@ -64,11 +71,47 @@ func (l *GenericLayout) AddStatic(model SizedModel, size int, style lipgloss.Sty
 		sizeMode:     Static,
 		size:         size,
 		gap:          gap,
+		inlineStyle:  style,
 		baseStyle:    style,
 		currentStyle: style,
 	})
 }
 
+// Remove detaches model from this layout
+// The returned command announces the removal so the root can drop the
+// model's event subscriptions; it is nil if model isn't a child
+func (l *GenericLayout) Remove(model SizedModel) tea.Cmd {
+	index := -1
+	for i, child := range l.children {
+		if child.model == model {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil
+	}
+
+	if index == l.focused {
+		model.OnBlur()
+		l.focused = -1
+	} else if index < l.focused {
+		l.focused--
+	}
+
+	// Anything inside the removed subtree can't stay on the focus stack
+	if removed, ok := model.(*GenericLayout); ok {
+		dropFocus(removed)
+	}
+
+	l.children = append(l.children[:index], l.children[index+1:]...)
+	l.layoutChildren()
+
+	return func() tea.Msg {
+		return ComponentRemovedMsg{Model: model}
+	}
+}
+
 func (l *GenericLayout) SetSize(width, height int) {
 	oldWidth, oldHeight := l.width, l.height
 	l.width = width
@@ -79,9 +122,12 @@ func (l *GenericLayout) SetSize(width, height int) {
 }
 
 func (l *GenericLayout) GetFocusState() FocusState {
+	if l.IsDisabled() {
+		return Disabled
+	}
 	// A layout is focusable if it has any focusable children
 	for _, child := range l.children {
-		if child.model.GetFocusState() != NotFocusable {
+		if canFocus(child.model.GetFocusState()) {
 			return Focusable
 		}
 	}
@@ -91,7 +137,7 @@ func (l *GenericLayout) GetFocusState() FocusState {
 func (l *GenericLayout) OnFocus(baseStyle lipgloss.Style) (lipgloss.Style, tea.Cmd) {
 	// When layout gains focus, DON'T focus children yet
 	// Children will be focused when we're pushed onto the focus stack
-	return baseStyle.Border(lipgloss.ThickBorder()), nil
+	return baseStyle, nil
 }
 
 // func (l *GenericLayout) OnBlur() {
@@ -130,7 +176,7 @@ func (l *GenericLayout) OnBlur() {
 // focusFirst focuses the first focusable child
 func (l *GenericLayout) focusFirst() tea.Cmd {
 	for i := range l.children {
-		if l.children[i].model.GetFocusState() != NotFocusable {
+		if canFocus(l.children[i].model.GetFocusState()) {
 			return l.focusChild(i)
 		}
 	}
@@ -193,6 +239,8 @@ func (l *GenericLayout) focusChild(index int) tea.Cmd {
 	l.focused = index
 	oldStyle := l.children[index].currentStyle
 	style, cmd := l.children[index].model.OnFocus(l.children[index].baseStyle)
+	l.children[index].focusStyle = style
+	style = focusPolicy.apply(style, l.children[index].model, false)
 	l.children[index].currentStyle = style
 
 	// Re-layout if frame size changed
@@ -204,6 +252,16 @@ func (l *GenericLayout) focusChild(index int) tea.Cmd {
 	return cmd
 }
 
+// focusedCaptures reports whether the focused child wants key for itself
+func (l *GenericLayout) focusedCaptures(key tea.KeyMsg) bool {
+	if l.focused < 0 || l.focused >= len(l.children) {
+		return false
+	}
+	model := l.children[l.focused].model
+	capturer, ok := model.(KeyCapturer)
+	return ok && !isDisabled(model) && capturer.CapturesKey(key)
+}
+
 // cycleForward moves focus to the next focusable child
 func (l *GenericLayout) cycleForward() tea.Cmd {
 	if len(l.children) == 0 {
@@ -218,7 +276,7 @@ func (l *GenericLayout) cycleForward() tea.Cmd {
 	next := start
 	for {
 		next = (next + 1) % len(l.children)
-		if l.children[next].model.GetFocusState() != NotFocusable {
+		if canFocus(l.children[next].model.GetFocusState()) {
 			return l.focusChild(next)
 		}
 		if next == start {
@@ -242,7 +300,7 @@ func (l *GenericLayout) cycleBackward() tea.Cmd {
 	prev := start
 	for {
 		prev = (prev - 1 + len(l.children)) % len(l.children)
-		if l.children[prev].model.GetFocusState() != NotFocusable {
+		if canFocus(l.children[prev].model.GetFocusState()) {
 			return l.focusChild(prev)
 		}
 		if prev == start {
@@ -468,20 +526,37 @@ func (l *GenericLayout) Init() tea.Cmd {
 }
 
 func (l *GenericLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Targeted messages go straight to their component
+	if targeted, ok := msg.(TargetedMsg); ok {
+		cmd, _ := l.deliver(targeted)
+		return l, cmd
+	}
+
+	// Everything that isn't user input reaches the whole tree
+	if !followsFocus(msg) {
+		return l, l.broadcast(msg)
+	}
+
 	// Only handle navigation if we're the current focus
 	if !isFocusCurrent(l) {
 		// Forward to focused child
 		if l.focused >= 0 && l.focused < len(l.children) {
-			model, cmd := l.children[l.focused].model.Update(msg)
-			l.children[l.focused].model = model.(SizedModel)
-			return l, cmd
+			return l, l.updateChild(l.focused, msg)
 		}
 		return l, nil
 	}
 
 	// We're current - handle our keys
 	if key, ok := msg.(tea.KeyMsg); ok {
-		switch key.String() {
+		// A child in an editing state gets first pick of keys it declares
+		if !bindings.Global.Matches(key, bindings.QuitProgram) && l.focusedCaptures(key) {
+			return l, l.updateChild(l.focused, msg)
+		}
+	}
+
+	if isKeyInput(msg) {
+		action, _ := bindings.Global.ActionFor(msg)
+		switch action {
 		case bindings.QuitProgram:
 			return l, tea.Quit
 
@@ -498,16 +573,14 @@ func (l *GenericLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				focusState := child.GetFocusState()
 
 				// If it's a layout, dive into it
-				if childLayout, ok := child.(*GenericLayout); ok && focusState != NotFocusable {
+				if childLayout, ok := child.(*GenericLayout); ok && canFocus(focusState) {
 					pushFocus(childLayout)
 					return l, childLayout.focusFirst()
 				}
 
 				// If it's interactive, let it handle enter
 				if focusState == Interactive {
-					model, cmd := child.Update(msg)
-					l.children[l.focused].model = model.(SizedModel)
-					return l, cmd
+					return l, l.updateChild(l.focused, msg)
 				}
 			}
 			return l, nil
@@ -527,11 +600,9 @@ func (l *GenericLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	// Forward other messages to focused child
+	// Forward other input to focused child
 	if l.focused >= 0 && l.focused < len(l.children) {
-		model, cmd := l.children[l.focused].model.Update(msg)
-		l.children[l.focused].model = model.(SizedModel)
-		return l, cmd
+		return l, l.updateChild(l.focused, msg)
 	}
 
 	return l, nil
@@ -546,6 +617,9 @@ func (l *GenericLayout) View() string {
 
 	for i, child := range l.children {
 		content := child.model.View()
+		if isDisabled(child.model) {
+			content = renderDisabled(content)
+		}
 		views = append(views, child.currentStyle.Render(content))
 
 		if i < len(l.children)-1 && child.gap > 0 {
@@ -0,0 +1,179 @@
+package layout
+
+import (
+	"strings"
+
+	"github.com/cactircool/bitwave/bindings"
+	"github.com/cactircool/bitwave/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// helpGroup is the bindings of one component on the focus path
+type helpGroup struct {
+	title  string
+	keymap *bindings.Keymap
+}
+
+// helpGroups returns the global bindings followed by the bindings of every
+// component along the focus path
+func helpGroups() []helpGroup {
+	groups := []helpGroup{{title: "Global", keymap: bindings.Global}}
+	for _, model := range focusPath() {
+		provider, ok := model.(KeymapProvider)
+		if !ok {
+			continue
+		}
+		keymap := provider.ActiveKeymap()
+		title := keymapTitle(keymap)
+		if ident, ok := model.(Identifiable); ok && ident.ID() != "" {
+			title += " · " + ident.ID()
+		}
+		groups = append(groups, helpGroup{title: title, keymap: keymap})
+	}
+	return groups
+}
+
+// keymapTitle turns a keymap name like "table_edit" into "Table edit"
+func keymapTitle(keymap *bindings.Keymap) string {
+	title := strings.ReplaceAll(keymap.Name(), "_", " ")
+	if title == "" {
+		return title
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// formatKeys renders the keys of a binding, e.g. "↑/k"
+func formatKeys(binding bindings.Binding) string {
+	keys := make([]string, len(binding.Keys))
+	for i, key := range binding.Keys {
+		keys[i] = bindings.DisplayKey(key)
+	}
+	return strings.Join(keys, "/")
+}
+
+// helpActions returns the actions of keymap worth listing in help
+func helpActions(keymap *bindings.Keymap) []bindings.Action {
+	actions := []bindings.Action{}
+	for _, action := range keymap.Actions() {
+		// The leader does nothing on its own
+		if action == bindings.Leader || len(keymap.Keys(action)) == 0 {
+			continue
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+// shortHelp renders a one-line summary of actions, e.g. "↑/k up • ↓/j down"
+// With no actions given every action in the keymap is listed
+func shortHelp(keymap *bindings.Keymap, actions ...bindings.Action) string {
+	if len(actions) == 0 {
+		actions = helpActions(keymap)
+	}
+
+	parts := make([]string, 0, len(actions))
+	for _, action := range actions {
+		binding, ok := keymap.Binding(action)
+		if !ok || len(binding.Keys) == 0 {
+			continue
+		}
+		parts = append(parts, formatKeys(binding)+" "+binding.Help)
+	}
+	return strings.Join(parts, " • ")
+}
+
+// helpView renders the full help overlay, one column per component
+func helpView() string {
+	t := theme.Current()
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(t.Color(theme.Title)).MarginBottom(1)
+	keyStyle := lipgloss.NewStyle().Bold(true).Foreground(t.Color(theme.Primary))
+
+	columns := []string{}
+	for _, group := range helpGroups() {
+		actions := helpActions(group.keymap)
+		if len(actions) == 0 {
+			continue
+		}
+
+		keys := make([]string, len(actions))
+		helps := make([]string, len(actions))
+		for i, action := range actions {
+			binding, _ := group.keymap.Binding(action)
+			keys[i] = keyStyle.Render(formatKeys(binding))
+			helps[i] = binding.Help
+		}
+
+		body := lipgloss.JoinHorizontal(lipgloss.Top,
+			strings.Join(keys, "\n"),
+			"  ",
+			strings.Join(helps, "\n"),
+		)
+		columns = append(columns, lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render(group.title), body))
+		columns = append(columns, "    ")
+	}
+	if len(columns) > 0 {
+		columns = columns[:len(columns)-1]
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Color(theme.Primary)).
+		Padding(0, 1).
+		Render(lipgloss.JoinHorizontal(lipgloss.Top, columns...))
+}
+
+// HelpBar is a one-line summary of the bindings along the focus path,
+// meant for a footer
+type HelpBar struct {
+	Identity
+	DisabledState
+	width  int
+	height int
+}
+
+func NewHelpBar() *HelpBar {
+	return &HelpBar{}
+}
+
+func (h *HelpBar) SetSize(width, height int) {
+	h.width = width
+	h.height = height
+}
+
+func (h *HelpBar) GetFocusState() FocusState {
+	return NotFocusable
+}
+
+func (h *HelpBar) OnFocus(baseStyle lipgloss.Style) (lipgloss.Style, tea.Cmd) {
+	return baseStyle, nil
+}
+
+func (h *HelpBar) OnBlur() {}
+
+func (h *HelpBar) Init() tea.Cmd {
+	return nil
+}
+
+func (h *HelpBar) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	return h, nil
+}
+
+func (h *HelpBar) View() string {
+	// Innermost component first, since the line gets truncated
+	groups := helpGroups()
+	parts := make([]string, 0, len(groups))
+	for i := len(groups) - 1; i >= 0; i-- {
+		if help := shortHelp(groups[i].keymap); help != "" {
+			parts = append(parts, help)
+		}
+	}
+
+	line := ansi.Truncate(strings.Join(parts, " | "), h.width, "…")
+	return lipgloss.NewStyle().
+		Foreground(theme.Current().Color(theme.Muted)).
+		Width(h.width).
+		Height(h.height).
+		Render(line)
+}
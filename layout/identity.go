@@ -0,0 +1,58 @@
+package layout
+
+// Identifiable is implemented by models that can be addressed by ID
+type Identifiable interface {
+	ID() string
+}
+
+// Identity gives a model an ID it can be addressed by, and classes it
+// can be styled by
+// Embed it in a model to make it Identifiable
+type Identity struct {
+	id      string
+	classes []string
+}
+
+func (i *Identity) ID() string {
+	return i.id
+}
+
+func (i *Identity) SetID(id string) {
+	i.id = id
+}
+
+// Classified is implemented by models that carry stylesheet classes
+type Classified interface {
+	Classes() []string
+}
+
+func (i *Identity) Classes() []string {
+	return i.classes
+}
+
+// AddClass tags the model with class for stylesheet selectors like ".sidebar"
+func (i *Identity) AddClass(classes ...string) {
+	for _, class := range classes {
+		if !i.HasClass(class) {
+			i.classes = append(i.classes, class)
+		}
+	}
+}
+
+func (i *Identity) RemoveClass(class string) {
+	for j, c := range i.classes {
+		if c == class {
+			i.classes = append(i.classes[:j], i.classes[j+1:]...)
+			return
+		}
+	}
+}
+
+func (i *Identity) HasClass(class string) bool {
+	for _, c := range i.classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package layout
 
 import (
+	"github.com/cactircool/bitwave/bindings"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -15,6 +16,8 @@ const (
 	Focusable
 	// Interactive - model captures keyboard input (e.g., textarea)
 	Interactive
+	// Disabled - model is switched off: dimmed, skipped by focus cycling, gets no input
+	Disabled
 )
 
 // SizedModel is the base interface all layout children must implement
@@ -29,10 +32,31 @@ type SizedModel interface {
 	OnBlur()
 }
 
+// KeyCapturer is implemented by interactive models that sometimes need keys
+// their layout would otherwise handle (e.g. Tab in an active textarea)
+type KeyCapturer interface {
+	// CapturesKey reports whether the model consumes key in its current state
+	CapturesKey(key tea.KeyMsg) bool
+}
+
+// Activatable is implemented by interactive models with an editing state,
+// which stylesheets can target with ":active"
+type Activatable interface {
+	IsActive() bool
+}
+
+// KeymapProvider is implemented by models with their own key bindings,
+// so the root can match key sequences against them
+type KeymapProvider interface {
+	// ActiveKeymap returns the keymap for the model's current state
+	ActiveKeymap() *bindings.Keymap
+}
+
 // Layout interface for containers that can hold children
 type Layout interface {
 	Add(model SizedModel, weight float64, style lipgloss.Style, gap int)
 	AddStatic(model SizedModel, size int, style lipgloss.Style, gap int)
+	Remove(model SizedModel) tea.Cmd
 }
 
 // LayoutModel combines both interfaces
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/cactircool/bitwave/bindings"
+	"github.com/cactircool/bitwave/theme"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -16,6 +18,8 @@ type ListItem struct {
 }
 
 type ListLayout struct {
+	Identity
+	DisabledState
 	items          []ListItem
 	width          int
 	height         int
@@ -28,47 +32,304 @@ type ListLayout struct {
 	selectedStyle  lipgloss.Style
 	selectedCursorStyle lipgloss.Style
 	titleStyle     lipgloss.Style
+	helpStyle      lipgloss.Style
+	matchStyle     lipgloss.Style // Characters matching the filter
+
+	truncation Truncation   // How items too wide for the list are cut
+	delegate   ItemDelegate // Draws the items
 
 	title          string
+	titleHighlighted bool // Set by the FocusTitle indicator
 	showHelp       bool
+
+	keymap         *bindings.Keymap
+	filterKeymap   *bindings.Keymap
+
+	// The filter hides items that don't fuzzy match it; it stays applied
+	// after typing until it's cleared
+	filtering   bool // Typing the filter
+	filterInput textinput.Model
+
+	// Range selection, see list_selection.go
+	anchor           int    // Item the range started at, -1 without a range
+	base             []bool // Selection from before the range
+	visual           bool
+	visualKeymap     *bindings.Keymap
+	selectingPattern bool // Typing a pattern for SelectMatching
+	patternInput     textinput.Model
+	patternKeymap    *bindings.Keymap
+
+	onActivate func(item ListItem) tea.Cmd // Run when an item is activated with Enter
+
+	// Editing, see list_edit.go
+	editable   bool
+	editing    bool // Typing an item
+	inserting  bool // The item being typed is new
+	editIndex  int
+	editor     textinput.Model
+	editKeymap *bindings.Keymap
+	undo       []listSnapshot
+
+	message string // Shown in place of the help until the next key, e.g. the selection limit
+
+	source         *ObservableList[string] // Optional bound source for items
+	sourceVersion  uint64
+
+	// Optional Data for items that arrive as bare values, from the bound
+	// source or typed in
+	newData func(value string) interface{}
 }
 
 func NewListLayout(title string, maxSelections int) *ListLayout {
-	return &ListLayout{
+	l := &ListLayout{
 		items:         []ListItem{},
 		cursor:        0,
 		scrollOffset:  0,
 		maxSelections: maxSelections,
 		title:         title,
 		showHelp:      true,
-		normalStyle:   lipgloss.NewStyle().Padding(0, 2),
-		cursorStyle:   lipgloss.NewStyle().
-			Background(lipgloss.Color("12")).
-			Foreground(lipgloss.Color("0")).
-			Bold(true).
-			Padding(0, 1),
-		selectedStyle: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("10")).
-			Padding(0, 1),
-		selectedCursorStyle: lipgloss.NewStyle().
-			Background(lipgloss.Color("10")).
-			Foreground(lipgloss.Color("0")).
-			Bold(true).
-			Padding(0, 1),
-		titleStyle: lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("13")).
-			Padding(0, 2).
-			MarginBottom(1),
+		keymap:        bindings.List.Clone(),
+		filterKeymap:  bindings.ListFilter.Clone(),
+		filterInput:   textinput.New(),
+		anchor:        -1,
+		visualKeymap:  bindings.ListVisual.Clone(),
+		patternInput:  textinput.New(),
+		patternKeymap: bindings.ListPattern.Clone(),
+		editor:        textinput.New(),
+		editKeymap:    bindings.ListEdit.Clone(),
+		truncation:    DefaultTruncation,
+		delegate:      DefaultDelegate{},
+	}
+	l.filterInput.Prompt = "/"
+	l.patternInput.Prompt = "select: "
+	l.editor.Prompt = ""
+	l.ApplyTheme(theme.Current())
+	return l
+}
+
+// ApplyTheme rebuilds the list's styles from t
+func (l *ListLayout) ApplyTheme(t *theme.Theme) {
+	l.normalStyle = lipgloss.NewStyle().
+		Foreground(t.Color(theme.Text)).
+		Padding(0, 2)
+	l.cursorStyle = lipgloss.NewStyle().
+		Background(t.Color(theme.Selection)).
+		Foreground(t.Color(theme.Inverse)).
+		Bold(true).
+		Padding(0, 1)
+	l.selectedStyle = lipgloss.NewStyle().
+		Foreground(t.Color(theme.Selected)).
+		Padding(0, 1)
+	l.selectedCursorStyle = lipgloss.NewStyle().
+		Background(t.Color(theme.Selected)).
+		Foreground(t.Color(theme.Inverse)).
+		Bold(true).
+		Padding(0, 1)
+	l.titleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Color(theme.Title)).
+		Padding(0, 2).
+		MarginBottom(1)
+	l.helpStyle = lipgloss.NewStyle().
+		Foreground(t.Color(theme.Muted)).
+		Padding(0, 2)
+	l.matchStyle = lipgloss.NewStyle().
+		Foreground(t.Color(theme.Highlight)).
+		Bold(true).
+		Underline(true)
+}
+
+// SetTruncation sets how items too wide for the list are cut
+func (l *ListLayout) SetTruncation(truncation Truncation) {
+	l.truncation = truncation
+}
+
+// SetDelegate changes how items are drawn; nil restores DefaultDelegate
+func (l *ListLayout) SetDelegate(delegate ItemDelegate) {
+	if delegate == nil {
+		delegate = DefaultDelegate{}
+	}
+	l.delegate = delegate
+	l.adjustScroll()
+}
+
+// itemStyles returns the styles handed to the delegate
+func (l *ListLayout) itemStyles() ItemStyles {
+	return ItemStyles{
+		Normal:         l.normalStyle,
+		Cursor:         l.cursorStyle,
+		Selected:       l.selectedStyle,
+		SelectedCursor: l.selectedCursorStyle,
+		Match:          l.matchStyle,
+		Muted:          l.helpStyle.UnsetPadding(),
+	}
+}
+
+// itemHeight returns how many lines the item at index takes
+func (l *ListLayout) itemHeight(index int) int {
+	if l.editing && index == l.editIndex {
+		return 1 // Typed on one line, whatever the delegate
+	}
+	return max(l.delegate.Height(l.items[index], l.width), 1)
+}
+
+func (l *ListLayout) SetTitleHighlighted(highlighted bool) {
+	l.titleHighlighted = highlighted
+}
+
+// Describe summarizes the list for the linear view, as of the last Update
+// It's called while rendering, so it doesn't sync the bound source
+func (l *ListLayout) Describe() string {
+	name := "List"
+	if l.title != "" {
+		name = l.title + " list"
+	}
+	if len(l.items) == 0 {
+		return name + ", empty"
+	}
+
+	shown := l.shown()
+	filter := ""
+	if l.Filter() != "" {
+		filter = fmt.Sprintf(", filtered by %q", l.Filter())
+	}
+	pos := l.cursorPosition(shown)
+	if pos < 0 {
+		return fmt.Sprintf("%s, no matches%s", name, filter)
+	}
+	item := l.items[l.cursor]
+	description := fmt.Sprintf("%s, item %d of %d: %s%s", name, pos+1, len(shown), item.Value, filter)
+	if item.Selected {
+		description += ", selected"
+	}
+	if l.visual {
+		description += ", visual mode"
+	}
+	if l.editing {
+		description += ", editing"
+	}
+	return description
+}
+
+// Keymap returns the list's keymap, which can be overridden per list
+func (l *ListLayout) Keymap() *bindings.Keymap {
+	return l.keymap
+}
+
+// FilterKeymap returns the keymap used while typing a filter
+func (l *ListLayout) FilterKeymap() *bindings.Keymap {
+	return l.filterKeymap
+}
+
+// VisualKeymap returns the keymap used in visual mode
+func (l *ListLayout) VisualKeymap() *bindings.Keymap {
+	return l.visualKeymap
+}
+
+// PatternKeymap returns the keymap used while typing a pattern to select
+func (l *ListLayout) PatternKeymap() *bindings.Keymap {
+	return l.patternKeymap
+}
+
+// ActiveKeymap returns the keymap for the list's current mode
+func (l *ListLayout) ActiveKeymap() *bindings.Keymap {
+	switch {
+	case l.editing:
+		return l.editKeymap
+	case l.filtering:
+		return l.filterKeymap
+	case l.selectingPattern:
+		return l.patternKeymap
+	case l.visual:
+		return l.visualKeymap
+	}
+	return l.keymap
+}
+
+func (l *ListLayout) SetKeymap(keymap *bindings.Keymap) {
+	l.keymap = keymap
+}
+
+func (l *ListLayout) SetFilterKeymap(keymap *bindings.Keymap) {
+	l.filterKeymap = keymap
+}
+
+// Filter returns the current filter, "" when every item is shown
+func (l *ListLayout) Filter() string {
+	return l.filterInput.Value()
+}
+
+// SetFilter shows only the items that fuzzy match filter, best match first
+// Selections on hidden items are kept
+func (l *ListLayout) SetFilter(filter string) {
+	l.filterInput.SetValue(filter)
+	l.filterChanged()
+}
+
+// ClearFilter shows every item again, keeping the cursor on its item
+func (l *ListLayout) ClearFilter() {
+	l.filtering = false
+	l.filterInput.Blur()
+	l.filterInput.SetValue("")
+	l.adjustScroll()
+}
+
+// filterChanged moves the cursor to the best match
+func (l *ListLayout) filterChanged() {
+	if shown := l.shown(); len(shown) > 0 {
+		l.cursor = shown[0].index
+	}
+	l.scrollOffset = 0
+	l.adjustScroll()
+}
+
+// shown returns the items that pass the filter, best match first, or every
+// item in order without a filter
+func (l *ListLayout) shown() []fuzzyResult {
+	values := make([]string, len(l.items))
+	for i, item := range l.items {
+		values[i] = item.Value
+	}
+	return fuzzyFilter(l.Filter(), values)
+}
+
+// cursorPosition returns where the cursor's item is in shown, or -1 if it's hidden
+func (l *ListLayout) cursorPosition(shown []fuzzyResult) int {
+	for pos, result := range shown {
+		if result.index == l.cursor {
+			return pos
+		}
+	}
+	return -1
+}
+
+// moveTo puts the cursor on the item at pos in shown
+func (l *ListLayout) moveTo(shown []fuzzyResult, pos int) {
+	if len(shown) == 0 {
+		return
 	}
+	pos = max(min(pos, len(shown)-1), 0)
+	l.cursor = shown[pos].index
+	l.adjustScroll()
+}
+
+// SetShowHelp shows or hides the status line at the bottom of the list
+func (l *ListLayout) SetShowHelp(show bool) {
+	l.showHelp = show
+	l.adjustScroll()
 }
 
 func (l *ListLayout) AddItem(value string, data interface{}) {
+	l.undo = nil // Undo would drop the new item
 	l.items = append(l.items, ListItem{
 		Value:    value,
 		Data:     data,
 		Selected: false,
 	})
+	if l.source != nil {
+		l.source.Append(value)
+		l.sourceVersion = l.source.Version()
+	}
 }
 
 func (l *ListLayout) AddItems(values []string) {
@@ -77,6 +338,70 @@ func (l *ListLayout) AddItems(values []string) {
 	}
 }
 
+// BindItems makes the list's items track source
+// Added and edited items are written back to source
+// Selections are kept for values that are still present after a change
+func (l *ListLayout) BindItems(source *ObservableList[string]) {
+	l.source = source
+	l.sourceVersion = source.Version() - 1 // Force the first sync
+	l.syncSource()
+}
+
+// syncSource rebuilds items from the bound source if it changed
+func (l *ListLayout) syncSource() {
+	if l.source == nil {
+		return
+	}
+	values, version := l.source.snapshot()
+	if version == l.sourceVersion {
+		return
+	}
+	l.sourceVersion = version
+
+	l.itemsReplaced()
+
+	data := map[string]interface{}{}
+	for _, item := range l.items {
+		data[item.Value] = item.Data
+	}
+	items := make([]ListItem, len(values))
+	for i, v := range values {
+		d, ok := data[v]
+		if !ok && l.newData != nil {
+			d = l.newData(v)
+		}
+		items[i] = ListItem{Value: v, Data: d}
+	}
+	l.items = l.withSelections(items)
+
+	if l.cursor >= len(l.items) {
+		l.cursor = len(l.items) - 1
+	}
+	if l.cursor < 0 {
+		l.cursor = 0
+	}
+	l.adjustScroll()
+}
+
+// withSelections returns items selected wherever the current items with the
+// same value are, matching duplicates in order
+func (l *ListLayout) withSelections(items []ListItem) []ListItem {
+	selected := map[string]int{}
+	for _, item := range l.items {
+		if item.Selected {
+			selected[item.Value]++
+		}
+	}
+	items = append([]ListItem(nil), items...)
+	for i := range items {
+		items[i].Selected = selected[items[i].Value] > 0
+		if items[i].Selected {
+			selected[items[i].Value]--
+		}
+	}
+	return items
+}
+
 func (l *ListLayout) GetSelectedItems() []ListItem {
 	selected := []ListItem{}
 	for _, item := range l.items {
@@ -106,18 +431,30 @@ func (l *ListLayout) ClearSelections() {
 func (l *ListLayout) SetSize(width, height int) {
 	l.width = width
 	l.height = height
+	l.adjustScroll()
 }
 
 func (l *ListLayout) GetFocusState() FocusState {
+	if l.IsDisabled() {
+		return Disabled
+	}
 	return Interactive
 }
 
 func (l *ListLayout) OnFocus(baseStyle lipgloss.Style) (lipgloss.Style, tea.Cmd) {
-	return baseStyle.Border(lipgloss.ThickBorder()), nil
+	// Focus is drawn by the layout's FocusPolicy
+	return baseStyle, nil
 }
 
 func (l *ListLayout) OnBlur() {
-	// Nothing special needed
+	// Stop typing but keep the filter and selection
+	l.filtering = false
+	l.filterInput.Blur()
+	l.selectingPattern = false
+	l.patternInput.Blur()
+	l.visual = false
+	l.endRange()
+	l.stopEditing()
 }
 
 func (l *ListLayout) Init() tea.Cmd {
@@ -125,68 +462,223 @@ func (l *ListLayout) Init() tea.Cmd {
 }
 
 func (l *ListLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if key, ok := msg.(tea.KeyMsg); ok {
-		switch key.String() {
-		case "up", "k":
-			if l.cursor > 0 {
-				l.cursor--
-				l.adjustScroll()
+	editing := l.editing
+	l.syncSource()
+	if editing && !l.editing && isKeyInput(msg) {
+		// The source changed under the edit; the key was typed for the editor
+		return l, nil
+	}
+
+	cursor := l.cursor
+	cmd := l.update(msg)
+	if l.cursor != cursor {
+		cmd = tea.Batch(cmd, l.cursorMoved())
+	}
+	return l, cmd
+}
+
+func (l *ListLayout) update(msg tea.Msg) tea.Cmd {
+	if isKeyInput(msg) {
+		l.message = ""
+		switch {
+		case l.editing:
+			return l.updateEdit(msg)
+		case l.filtering:
+			return l.updateFilter(msg)
+		case l.selectingPattern:
+			return l.updatePattern(msg)
+		case l.visual:
+			return l.updateVisual(msg)
+		}
+		if l.Filter() != "" && l.filterKeymap.Matches(msg, bindings.Cancel) {
+			l.ClearFilter()
+			return nil
+		}
+
+		shown := l.shown()
+		pos := l.cursorPosition(shown)
+		action, _ := l.keymap.ActionFor(msg)
+		if action != bindings.ExtendUp && action != bindings.ExtendDown {
+			l.endRange()
+		}
+		switch action {
+		case bindings.Up:
+			if pos > 0 {
+				l.moveTo(shown, pos-1)
 			}
-			return l, nil
+			return nil
 
-		case "down", "j":
-			if l.cursor < len(l.items)-1 {
-				l.cursor++
-				l.adjustScroll()
+		case bindings.Down:
+			if pos < len(shown)-1 {
+				l.moveTo(shown, pos+1)
 			}
-			return l, nil
+			return nil
 
-		case "g":
+		case bindings.Top:
 			// Go to top
-			l.cursor = 0
-			l.adjustScroll()
-			return l, nil
+			l.moveTo(shown, 0)
+			return nil
 
-		case "G":
+		case bindings.Bottom:
 			// Go to bottom
-			l.cursor = len(l.items) - 1
-			l.adjustScroll()
-			return l, nil
-
-		case " ", bindings.CycleEnter:
-			// Toggle selection
-			if l.cursor >= 0 && l.cursor < len(l.items) {
-				l.toggleSelection(l.cursor)
+			l.moveTo(shown, len(shown)-1)
+			return nil
+
+		case bindings.Toggle:
+			// Toggle selection, unless the cursor's item is filtered out
+			if pos >= 0 && l.toggleSelection(l.cursor) {
+				return l.publishSelection()
 			}
-			return l, nil
+			return nil
 
-		case "a":
-			// Select all (if unlimited or within limit)
-			if l.maxSelections == 0 || l.maxSelections >= len(l.items) {
-				for i := range l.items {
-					l.items[i].Selected = true
+		case bindings.Activate:
+			if pos < 0 {
+				return nil
+			}
+			return l.activate()
+
+		case bindings.SelectAll:
+			// Select all shown items (if unlimited or within limit)
+			selected := 0
+			for i, item := range l.items {
+				if item.Selected && !l.isShown(shown, i) {
+					selected++ // Hidden selections stay
+				}
+			}
+			if l.maxSelections == 0 || l.maxSelections >= selected+len(shown) {
+				if l.setShownSelected(shown, true) {
+					return l.publishSelection()
 				}
+				return nil
 			}
-			return l, nil
+			l.limitReached()
+			return nil
 
-		case "A":
-			// Deselect all
-			l.ClearSelections()
-			return l, nil
+		case bindings.SelectNone:
+			// Deselect all shown items
+			if l.setShownSelected(shown, false) {
+				return l.publishSelection()
+			}
+			return nil
+
+		case bindings.Filter:
+			l.filtering = true
+			return l.filterInput.Focus()
+
+		case bindings.ExtendUp, bindings.ExtendDown:
+			// Select from where the range started to the new cursor
+			if pos < 0 {
+				return nil
+			}
+			l.startRange()
+			if action == bindings.ExtendUp {
+				l.moveTo(shown, pos-1)
+			} else {
+				l.moveTo(shown, pos+1)
+			}
+			if l.applyRange(shown) {
+				return l.publishSelection()
+			}
+			return nil
+
+		case bindings.Visual:
+			if pos < 0 {
+				return nil
+			}
+			l.visual = true
+			l.startRange()
+			if l.applyRange(shown) {
+				return l.publishSelection()
+			}
+			return nil
+
+		case bindings.InvertSelection:
+			if l.InvertSelection() {
+				return l.publishSelection()
+			}
+			return nil
+
+		case bindings.SelectMatching:
+			l.selectingPattern = true
+			l.patternInput.SetValue("")
+			return l.patternInput.Focus()
+
+		case bindings.InsertItem, bindings.RenameItem, bindings.DeleteItem,
+			bindings.MoveUp, bindings.MoveDown, bindings.Undo:
+			if !l.editable || action != bindings.InsertItem && pos < 0 {
+				return nil
+			}
+			return l.updateEditing(action)
+
+		}
+	}
+
+	return nil
+}
+
+// updateFilter handles a key while the filter is being typed
+func (l *ListLayout) updateFilter(msg tea.Msg) tea.Cmd {
+	shown := l.shown()
+	action, _ := l.filterKeymap.ActionFor(msg)
+	switch action {
+	case bindings.Up:
+		l.moveTo(shown, l.cursorPosition(shown)-1)
+		return nil
+	case bindings.Down:
+		l.moveTo(shown, l.cursorPosition(shown)+1)
+		return nil
+	case bindings.Run:
+		// Keep the filter and go back to navigating
+		l.filtering = false
+		l.filterInput.Blur()
+		return nil
+	case bindings.Cancel:
+		l.ClearFilter()
+		return nil
+	}
+
+	filter := l.Filter()
+	var cmd tea.Cmd
+	l.filterInput, cmd = l.filterInput.Update(msg)
+	if l.Filter() != filter {
+		l.filterChanged()
+	}
+	return cmd
+}
 
-		case "?":
-			// Toggle help
-			l.showHelp = !l.showHelp
-			return l, nil
+func (l *ListLayout) isShown(shown []fuzzyResult, index int) bool {
+	for _, result := range shown {
+		if result.index == index {
+			return true
 		}
 	}
+	return false
+}
 
-	return l, nil
+// CapturesKey claims every key while typing an item, filter or pattern, and the
+// cancel keys of visual mode and an applied filter so they aren't taken for
+// leaving the layout
+func (l *ListLayout) CapturesKey(key tea.KeyMsg) bool {
+	switch {
+	case l.editing || l.filtering || l.selectingPattern:
+		return true
+	case l.visual:
+		return l.visualKeymap.Matches(key, bindings.Cancel) || l.visualKeymap.Matches(key, bindings.Visual)
+	}
+	return l.Filter() != "" && l.filterKeymap.Matches(key, bindings.Cancel)
 }
 
-func (l *ListLayout) toggleSelection(index int) {
+// IsActive reports whether an item, filter or pattern is being typed, or
+// visual mode is on
+func (l *ListLayout) IsActive() bool {
+	return l.editing || l.filtering || l.selectingPattern || l.visual
+}
+
+// toggleSelection flips the selection of the item at index and reports
+// whether it changed, which it doesn't over maxSelections
+func (l *ListLayout) toggleSelection(index int) bool {
 	if index < 0 || index >= len(l.items) {
-		return
+		return false
 	}
 
 	item := &l.items[index]
@@ -210,25 +702,61 @@ func (l *ListLayout) toggleSelection(index int) {
 		} else if l.maxSelections == 0 || selectedCount < l.maxSelections {
 			// Can select more
 			item.Selected = true
+		} else {
+			l.limitReached()
+			return false
 		}
-		// Otherwise do nothing (max reached)
 	}
+	return true
 }
 
-func (l *ListLayout) adjustScroll() {
-	visibleItems := l.height - 2 // Account for title and help
-	if l.showHelp {
-		visibleItems -= 2
+// publishSelection announces the current selection on the event bus
+// and sends ListSelectionChangedMsg
+func (l *ListLayout) publishSelection() tea.Cmd {
+	selected := l.GetSelectedItems()
+	msg := ListSelectionChangedMsg{ID: l.ID(), Source: l, Selected: selected}
+	return tea.Batch(
+		Publish(SelectionChangedEvent{Source: l, Selected: selected}),
+		func() tea.Msg { return msg },
+	)
+}
+
+// listRows returns how many lines fit items between the title, filter and help
+func (l *ListLayout) listRows() int {
+	rows := l.height
+	if l.title != "" {
+		rows -= 2 // Title and its margin
+	}
+	if l.showHelp || l.message != "" {
+		rows -= 2
+	}
+	if l.filtering || l.selectingPattern || l.Filter() != "" {
+		rows--
 	}
-	if visibleItems < 1 {
-		visibleItems = 1
+	return max(rows, 1)
+}
+
+// adjustScroll scrolls by whole items until the cursor's item fits
+func (l *ListLayout) adjustScroll() {
+	rows := l.listRows()
+	shown := l.shown()
+	pos := l.cursorPosition(shown)
+	if pos < 0 {
+		l.scrollOffset = 0
+		return
 	}
 
-	if l.cursor < l.scrollOffset {
-		l.scrollOffset = l.cursor
+	if pos < l.scrollOffset {
+		l.scrollOffset = pos
 	}
-	if l.cursor >= l.scrollOffset+visibleItems {
-		l.scrollOffset = l.cursor - visibleItems + 1
+	// Drop items off the top until everything down to the cursor fits
+	used := 0
+	for i := l.scrollOffset; i <= pos; i++ {
+		used += l.itemHeight(shown[i].index)
+	}
+	for used > rows && l.scrollOffset < pos {
+		used -= l.itemHeight(shown[l.scrollOffset].index)
+		l.scrollOffset++
 	}
 
 	if l.scrollOffset < 0 {
@@ -341,91 +869,83 @@ func (l *ListLayout) View() string {
 	var b strings.Builder
 
 	// Title
-	titleHeight := 0
 	if l.title != "" {
-		b.WriteString(l.titleStyle.Render(l.title))
+		titleStyle := l.titleStyle
+		if l.titleHighlighted {
+			t := theme.Current()
+			titleStyle = titleStyle.
+				Background(t.Color(theme.Selection)).
+				Foreground(t.Color(theme.Inverse))
+		}
+		b.WriteString(titleStyle.Render(l.title))
 		b.WriteString("\n")
-		titleHeight = 2 // title + newline
 	}
 
-	// Calculate visible items
-	helpHeight := 0
-	if l.showHelp {
-		helpHeight = 2
+	// Filter, or the pattern being typed to select
+	if l.selectingPattern {
+		b.WriteString(lipgloss.NewStyle().Padding(0, 2).MaxWidth(l.width).Render(l.patternInput.View()))
+		b.WriteString("\n")
+	} else if l.filtering {
+		b.WriteString(lipgloss.NewStyle().Padding(0, 2).MaxWidth(l.width).Render(l.filterInput.View()))
+		b.WriteString("\n")
+	} else if l.Filter() != "" {
+		b.WriteString(l.helpStyle.MaxWidth(l.width).Render("/" + l.Filter()))
+		b.WriteString("\n")
 	}
 
-	visibleItems := l.height - titleHeight - helpHeight
-	if visibleItems < 1 {
-		visibleItems = 1
-	}
+	// Calculate visible lines
+	visibleLines := l.listRows()
+	shown := l.shown()
 
-	// Render items
-	endIdx := min(l.scrollOffset+visibleItems, len(l.items))
+	// Render whole items while they fit; an item taller than the list is cut
+	styles := l.itemStyles()
 	renderedLines := 0
-
-	for i := l.scrollOffset; i < endIdx && renderedLines < visibleItems; i++ {
-		item := l.items[i]
-
-		// Determine styling
-		var style lipgloss.Style
-		var prefix string
-
-		if i == l.cursor && item.Selected {
-			style = l.selectedCursorStyle
-			prefix = "▶ ✓ "
-		} else if i == l.cursor {
-			style = l.cursorStyle
-			prefix = "▶ "
-		} else if item.Selected {
-			style = l.selectedStyle
-			prefix = "  ✓ "
-		} else {
-			style = l.normalStyle
-			prefix = "    "
+	for pos := min(l.scrollOffset, len(shown)); pos < len(shown) && renderedLines < visibleLines; pos++ {
+		result := shown[pos]
+		height := l.itemHeight(result.index)
+		if renderedLines+height > visibleLines && renderedLines > 0 {
+			break
 		}
 
-		// Truncate if needed
-		// maxWidth := l.width - len(prefix) - 4
-		// if maxWidth < 0 {
-		// 	maxWidth = 0
-		// }
-		// text := item.Value
-		// if maxWidth > 3 && len(text) > maxWidth {
-		// 	text = text[:maxWidth-3] + "..."
-		// } else if len(text) > maxWidth && maxWidth > 0 {
-		// 	text = text[:maxWidth]
-		// } else if maxWidth == 0 {
-		// 	text = ""
-		// }
-
-		// b.WriteString(style.Render(prefix + text))
-		// Calculate available width (account for style padding)
-		stylePadding := style.GetHorizontalFrameSize()
-		maxWidth := l.width - len(prefix) - stylePadding
-		if maxWidth < 0 {
-			maxWidth = 0
+		item := l.items[result.index]
+		if l.editing && result.index == l.editIndex {
+			// The item being typed is drawn as its editor
+			style := l.cursorStyle
+			l.editor.Width = max(l.width-style.GetHorizontalFrameSize()-3, 1)
+			b.WriteString(style.Width(l.width).Render("✎ " + l.editor.View()))
+			b.WriteString("\n")
+			renderedLines++
+			continue
 		}
-
-		text := item.Value
-		if maxWidth > 3 && len(text) > maxWidth {
-			text = text[:maxWidth-3] + "..."
-		} else if len(text) > maxWidth && maxWidth > 0 {
-			text = text[:maxWidth]
-		} else if maxWidth == 0 {
-			text = ""
+		rendered := l.delegate.Render(item, ItemState{
+			Index:      result.index,
+			Width:      l.width,
+			Cursor:     result.index == l.cursor,
+			Selected:   item.Selected,
+			Matches:    result.positions,
+			Styles:     styles,
+			Truncation: l.truncation,
+		})
+		lines := strings.Split(rendered, "\n")
+		lines = lines[:min(len(lines), visibleLines-renderedLines)]
+		for _, line := range lines {
+			b.WriteString(line)
+			b.WriteString("\n")
 		}
+		renderedLines += len(lines)
+	}
 
-		// Render with explicit width to fill the space
-		rendered := style.Width(l.width - stylePadding).Render(prefix + text)
-		b.WriteString(rendered)
+	// Fill remaining space with blank lines
+	for renderedLines < visibleLines {
 		b.WriteString("\n")
 		renderedLines++
 	}
 
-	// Fill remaining space with blank lines
-	for renderedLines < visibleItems {
+	// Messages take the help's place, even when it's hidden
+	if l.message != "" {
 		b.WriteString("\n")
-		renderedLines++
+		b.WriteString(l.helpStyle.Foreground(theme.Current().Color(theme.Error)).MaxWidth(l.width).Render(l.message))
+		return b.String()
 	}
 
 	// Help text at bottom
@@ -437,21 +957,32 @@ func (l *ListLayout) View() string {
 			}
 		}
 
-		helpStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("240")).
-			Padding(0, 2)
-
 		var helpText string
-		if l.maxSelections == 1 {
-			helpText = fmt.Sprintf("↑↓=Move Space/Enter=Select ?=Help | Selected: %d", selectedCount)
+		if l.editing {
+			helpText = shortHelp(l.editKeymap)
+		} else if l.filtering {
+			helpText = shortHelp(l.filterKeymap)
+		} else if l.selectingPattern {
+			helpText = shortHelp(l.patternKeymap)
+		} else if l.visual {
+			helpText = fmt.Sprintf("-- VISUAL -- %s | %d selected", shortHelp(l.visualKeymap, bindings.Visual, bindings.Cancel), selectedCount)
+		} else if l.maxSelections == 1 {
+			helpText = fmt.Sprintf("%s | Selected: %d", shortHelp(l.keymap, bindings.Up, bindings.Down, bindings.Toggle, bindings.Filter), selectedCount)
 		} else if l.maxSelections > 0 {
-			helpText = fmt.Sprintf("↑↓=Move Space=Toggle a/A=All/None ?=Help | %d/%d selected", selectedCount, l.maxSelections)
+			helpText = fmt.Sprintf("%s | %d/%d selected", shortHelp(l.keymap, bindings.Up, bindings.Down, bindings.Toggle, bindings.SelectAll, bindings.SelectNone, bindings.Filter), selectedCount, l.maxSelections)
 		} else {
-			helpText = fmt.Sprintf("↑↓=Move Space=Toggle a/A=All/None g/G=Top/Bottom ?=Help | %d selected", selectedCount)
+			helpText = fmt.Sprintf("%s | %d selected", shortHelp(l.keymap, bindings.Up, bindings.Down, bindings.Toggle, bindings.SelectAll, bindings.SelectNone, bindings.Filter), selectedCount)
+		}
+		if l.editable && !l.editing {
+			helpText += " | " + shortHelp(l.keymap, bindings.InsertItem, bindings.RenameItem, bindings.DeleteItem, bindings.Undo)
+		}
+		if l.filtering || l.Filter() != "" {
+			// First, so narrow lists don't cut it off
+			helpText = fmt.Sprintf("%d/%d match | %s", len(shown), len(l.items), helpText)
 		}
 
 		b.WriteString("\n")
-		b.WriteString(helpStyle.Render(helpText))
+		b.WriteString(l.helpStyle.MaxWidth(l.width).Render(helpText))
 	}
 
 	return b.String()
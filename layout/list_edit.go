@@ -0,0 +1,267 @@
+package layout
+
+import (
+	"github.com/cactircool/bitwave/bindings"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxListUndo is how many edits an editable list can undo
+const maxListUndo = 100
+
+// ListEditKind is what a ListEditedMsg reports
+type ListEditKind int
+
+const (
+	ListItemInserted ListEditKind = iota
+	ListItemRenamed
+	ListItemDeleted
+	ListItemMoved
+	ListEditUndone
+)
+
+// ListEditedMsg is sent after the user changes an editable list's items
+type ListEditedMsg struct {
+	ID       string
+	Source   *ListLayout
+	Kind     ListEditKind
+	Index    int // Where the item is now; for deletes, where it was
+	From     int // Where a moved item was
+	Item     ListItem
+	OldValue string // The value before a rename
+	Items    []ListItem
+}
+
+// listSnapshot is the item order an undo goes back to
+// Selections aren't part of it, so undo keeps what was selected since
+type listSnapshot struct {
+	items  []ListItem
+	cursor int
+}
+
+// SetEditable lets the user insert, rename, delete and reorder items
+// Inserted items carry no Data, and renamed items keep theirs
+func (l *ListLayout) SetEditable(editable bool) {
+	l.editable = editable
+	if !editable {
+		l.stopEditing()
+	}
+}
+
+// EditKeymap returns the keymap used while an item is being typed
+func (l *ListLayout) EditKeymap() *bindings.Keymap {
+	return l.editKeymap
+}
+
+// saveUndo remembers items and cursor from before an edit
+func (l *ListLayout) saveUndo(items []ListItem, cursor int) {
+	snapshot := listSnapshot{items: make([]ListItem, len(items)), cursor: cursor}
+	for i, item := range items {
+		snapshot.items[i] = ListItem{Value: item.Value, Data: item.Data}
+	}
+	l.undo = append(l.undo, snapshot)
+	if len(l.undo) > maxListUndo {
+		l.undo = l.undo[1:]
+	}
+}
+
+// itemsReplaced forgets the undo history and cancels any edit after the
+// items were replaced outside the editor, so neither points at stale items
+func (l *ListLayout) itemsReplaced() {
+	if l.editing {
+		l.stopEditing()
+		l.message = "Items changed, edit cancelled"
+	}
+	l.undo = nil
+}
+
+// edited writes the items through to the bound source and sends the change
+func (l *ListLayout) edited(msg ListEditedMsg) tea.Cmd {
+	l.endRange()
+	l.writeSource()
+	l.adjustScroll()
+
+	msg.ID, msg.Source = l.ID(), l
+	msg.Items = append([]ListItem(nil), l.items...)
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// writeSource replaces the bound source's values with the items' values
+func (l *ListLayout) writeSource() {
+	if l.source == nil {
+		return
+	}
+	values := make([]string, len(l.items))
+	for i, item := range l.items {
+		values[i] = item.Value
+	}
+	l.source.Replace(values)
+	l.sourceVersion = l.source.Version()
+}
+
+// unfiltered reports whether every item is shown, and otherwise says why
+// an edit that depends on item order can't be done
+func (l *ListLayout) unfiltered() bool {
+	if l.Filter() != "" {
+		l.message = "Clear the filter to insert or move items"
+		return false
+	}
+	return true
+}
+
+// startInsert adds an empty item below the cursor and starts typing it
+func (l *ListLayout) startInsert() tea.Cmd {
+	if !l.unfiltered() {
+		return nil
+	}
+	index := 0
+	if len(l.items) > 0 {
+		index = l.cursor + 1
+	}
+	l.items = append(l.items, ListItem{})
+	copy(l.items[index+1:], l.items[index:])
+	l.items[index] = ListItem{}
+	l.cursor = index
+	l.adjustScroll()
+
+	l.editing, l.inserting, l.editIndex = true, true, index
+	l.editor.SetValue("")
+	return l.editor.Focus()
+}
+
+// startRename starts typing over the cursor's item
+func (l *ListLayout) startRename() tea.Cmd {
+	if l.cursor < 0 || l.cursor >= len(l.items) {
+		return nil
+	}
+	l.editing, l.inserting, l.editIndex = true, false, l.cursor
+	l.editor.SetValue(l.items[l.cursor].Value)
+	l.editor.CursorEnd()
+	return l.editor.Focus()
+}
+
+func (l *ListLayout) stopEditing() {
+	if l.editing && l.inserting && l.editIndex < len(l.items) {
+		// An item that was never saved goes away again
+		l.items = append(l.items[:l.editIndex], l.items[l.editIndex+1:]...)
+		l.cursor = max(l.editIndex-1, 0)
+		l.adjustScroll()
+	}
+	l.editing, l.inserting = false, false
+	l.editor.Blur()
+}
+
+// saveEdit stores what was typed
+func (l *ListLayout) saveEdit() tea.Cmd {
+	value := l.editor.Value()
+	index, inserting := l.editIndex, l.inserting
+	if value == "" || index < 0 || index >= len(l.items) {
+		// An empty item is a cancelled insert, and a rename to nothing is a no-op
+		// Nor can an item the list no longer has be saved
+		l.stopEditing()
+		return nil
+	}
+	l.editing, l.inserting = false, false
+	l.editor.Blur()
+
+	if inserting {
+		// Undo goes back to the list without the new item
+		l.saveUndo(append(l.items[:index:index], l.items[index+1:]...), max(index-1, 0))
+		l.items[index].Value = value
+		if l.newData != nil {
+			l.items[index].Data = l.newData(value)
+		}
+		return l.edited(ListEditedMsg{Kind: ListItemInserted, Index: index, Item: l.items[index]})
+	}
+
+	old := l.items[index].Value
+	if value == old {
+		return nil
+	}
+	l.saveUndo(l.items, l.cursor)
+	l.items[index].Value = value
+	return l.edited(ListEditedMsg{Kind: ListItemRenamed, Index: index, Item: l.items[index], OldValue: old})
+}
+
+// deleteItem removes the cursor's item
+func (l *ListLayout) deleteItem() tea.Cmd {
+	if l.cursor < 0 || l.cursor >= len(l.items) {
+		return nil
+	}
+	l.saveUndo(l.items, l.cursor)
+	index := l.cursor
+	item := l.items[index]
+	l.items = append(l.items[:index], l.items[index+1:]...)
+	l.cursor = max(min(l.cursor, len(l.items)-1), 0)
+	l.message = "Deleted " + item.Value + ", " + shortHelp(l.keymap, bindings.Undo)
+	return l.edited(ListEditedMsg{Kind: ListItemDeleted, Index: index, Item: item})
+}
+
+// moveItem swaps the cursor's item with its neighbour delta away
+func (l *ListLayout) moveItem(delta int) tea.Cmd {
+	if !l.unfiltered() {
+		return nil
+	}
+	from, to := l.cursor, l.cursor+delta
+	if from < 0 || to < 0 || to >= len(l.items) {
+		return nil
+	}
+	l.saveUndo(l.items, l.cursor)
+	l.items[from], l.items[to] = l.items[to], l.items[from]
+	l.cursor = to
+	return l.edited(ListEditedMsg{Kind: ListItemMoved, Index: to, From: from, Item: l.items[to]})
+}
+
+// undoEdit puts the items back to before the last edit, returning false if
+// there's nothing to undo
+func (l *ListLayout) undoEdit() bool {
+	if len(l.undo) == 0 {
+		return false
+	}
+	last := l.undo[len(l.undo)-1]
+	l.undo = l.undo[:len(l.undo)-1]
+	l.items = l.withSelections(last.items)
+	l.cursor = max(min(last.cursor, len(l.items)-1), 0)
+	l.adjustScroll()
+	return true
+}
+
+// updateEdit handles a key while an item is being typed
+func (l *ListLayout) updateEdit(msg tea.Msg) tea.Cmd {
+	action, _ := l.editKeymap.ActionFor(msg)
+	switch action {
+	case bindings.Save:
+		return l.saveEdit()
+	case bindings.Cancel:
+		l.stopEditing()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	l.editor, cmd = l.editor.Update(msg)
+	return cmd
+}
+
+// updateEditing handles the editing actions of the list keymap
+func (l *ListLayout) updateEditing(action bindings.Action) tea.Cmd {
+	switch action {
+	case bindings.InsertItem:
+		return l.startInsert()
+	case bindings.RenameItem:
+		return l.startRename()
+	case bindings.DeleteItem:
+		return l.deleteItem()
+	case bindings.MoveUp:
+		return l.moveItem(-1)
+	case bindings.MoveDown:
+		return l.moveItem(1)
+	case bindings.Undo:
+		if !l.undoEdit() {
+			l.message = "Nothing to undo"
+			return nil
+		}
+		return l.edited(ListEditedMsg{Kind: ListEditUndone, Index: l.cursor})
+	}
+	return nil
+}
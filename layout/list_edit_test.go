@@ -0,0 +1,151 @@
+package layout
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// key returns the message for a key name like "enter", "up" or "x"
+func key(name string) tea.KeyMsg {
+	switch name {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEscape}
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "shift+up":
+		return tea.KeyMsg{Type: tea.KeyShiftUp}
+	case "shift+down":
+		return tea.KeyMsg{Type: tea.KeyShiftDown}
+	case "space":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}
+}
+
+// press sends each key to model in turn
+func press(model tea.Model, keys ...string) {
+	for _, name := range keys {
+		model.Update(key(name))
+	}
+}
+
+func listValues(l *ListLayout) []string {
+	values := []string{}
+	for _, item := range l.items {
+		values = append(values, item.Value)
+	}
+	return values
+}
+
+func TestListEdit(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []string
+		keys  []string
+		want  []string
+	}{
+		{"insert into empty list", nil, []string{"o", "a", "enter"}, []string{"a"}},
+		{"insert below cursor", []string{"a", "b"}, []string{"o", "x", "enter"}, []string{"a", "x", "b"}},
+		{"cancelled insert", []string{"a", "b"}, []string{"o", "x", "esc"}, []string{"a", "b"}},
+		{"empty insert", []string{"a"}, []string{"o", "enter"}, []string{"a"}},
+		{"cancelled insert into empty list", nil, []string{"o", "esc"}, []string{}},
+		{"rename", []string{"a", "b"}, []string{"j", "r", "c", "enter"}, []string{"a", "bc"}},
+		{"delete last", []string{"a", "b"}, []string{"G", "d", "d"}, []string{}},
+		{"delete from empty list", nil, []string{"d"}, []string{}},
+		{"undo delete", []string{"a", "b"}, []string{"d", "u"}, []string{"a", "b"}},
+		{"undo insert", []string{"a"}, []string{"o", "x", "enter", "u"}, []string{"a"}},
+		{"undo with nothing to undo", []string{"a"}, []string{"u", "u"}, []string{"a"}},
+		{"move down then up", []string{"a", "b", "c"}, []string{"alt+j", "alt+j"}, []string{"b", "c", "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewListLayout("", 0)
+			l.SetEditable(true)
+			l.SetSize(20, 10)
+			l.AddItems(tt.items)
+			for _, name := range tt.keys {
+				if name == "alt+j" {
+					l.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}, Alt: true})
+					continue
+				}
+				press(l, name)
+			}
+			if got := listValues(l); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("items = %q, want %q", got, tt.want)
+			}
+			if len(l.items) > 0 && (l.cursor < 0 || l.cursor >= len(l.items)) {
+				t.Errorf("cursor %d out of %d items", l.cursor, len(l.items))
+			}
+			l.View()
+		})
+	}
+}
+
+func TestListEditShrinkingSource(t *testing.T) {
+	tests := []struct {
+		name  string
+		start []string // Keys that start the edit
+	}{
+		{"rename last", []string{"G", "r"}},
+		{"insert after last", []string{"G", "o"}},
+		{"insert after first", []string{"o"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewObservableList("a", "b", "c")
+			l := NewListLayout("", 0)
+			l.SetEditable(true)
+			l.SetSize(20, 10)
+			l.BindItems(source)
+
+			shrinkWhile(l, source, tt.start, "c")
+			press(l, "x", "enter")
+
+			if l.editing {
+				t.Error("still editing after the source changed")
+			}
+			if got := source.Items(); !reflect.DeepEqual(got, []string{"c"}) {
+				t.Errorf("source = %q, want [c]", got)
+			}
+			l.View()
+		})
+	}
+}
+
+func TestListUndoAfterExternalChange(t *testing.T) {
+	source := NewObservableList("a", "b", "c")
+	l := NewListLayout("", 0)
+	l.SetEditable(true)
+	l.SetSize(20, 10)
+	l.BindItems(source)
+
+	press(l, "d")
+	source.Append("d")
+	press(l, "u")
+
+	if got := source.Items(); !reflect.DeepEqual(got, []string{"b", "c", "d"}) {
+		t.Errorf("source = %q, want undo to leave the external change alone", got)
+	}
+}
+
+func TestListUndoKeepsSelections(t *testing.T) {
+	l := NewListLayout("", 0)
+	l.SetEditable(true)
+	l.SetSize(20, 10)
+	l.AddItems([]string{"a", "b", "c"})
+
+	press(l, "G", "d", "up", "space", "u")
+
+	if got := listValues(l); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("items = %q, want [a b c]", got)
+	}
+	if !l.items[0].Selected {
+		t.Error("undo dropped a selection made after the edit")
+	}
+}
@@ -0,0 +1,65 @@
+package layout
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Messages sent by ListLayout
+// They fan out through the tree like any message that isn't user input, so
+// parents and models wrapping the root can react to them
+// ID is the list's ID, which is empty until it's set with SetID; give each
+// list an ID, or compare Source, for one handler to tell several lists apart
+
+// ListCursorMovedMsg is sent when the cursor moves to another item
+type ListCursorMovedMsg struct {
+	ID     string
+	Source *ListLayout
+	Index  int // Index of the item, ignoring the filter
+	Item   ListItem
+}
+
+// ListSelectionChangedMsg is sent when items are selected or deselected
+type ListSelectionChangedMsg struct {
+	ID       string
+	Source   *ListLayout
+	Selected []ListItem
+}
+
+// ListItemActivatedMsg is sent when the item under the cursor is activated
+// with Enter
+type ListItemActivatedMsg struct {
+	ID     string
+	Source *ListLayout
+	Index  int
+	Item   ListItem
+}
+
+// SetOnActivate runs fn when an item is activated with Enter
+// Enter only activates; Space toggles the selection
+func (l *ListLayout) SetOnActivate(fn func(item ListItem) tea.Cmd) {
+	l.onActivate = fn
+}
+
+// cursorMoved returns the message for the cursor's new item
+func (l *ListLayout) cursorMoved() tea.Cmd {
+	if l.cursor < 0 || l.cursor >= len(l.items) {
+		return nil
+	}
+	msg := ListCursorMovedMsg{ID: l.ID(), Source: l, Index: l.cursor, Item: l.items[l.cursor]}
+	return func() tea.Msg {
+		return msg
+	}
+}
+
+// activate sends ListItemActivatedMsg for the cursor's item and runs the callback
+func (l *ListLayout) activate() tea.Cmd {
+	item := l.items[l.cursor]
+	msg := ListItemActivatedMsg{ID: l.ID(), Source: l, Index: l.cursor, Item: item}
+	cmds := []tea.Cmd{func() tea.Msg {
+		return msg
+	}}
+	if l.onActivate != nil {
+		cmds = append(cmds, l.onActivate(item))
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,111 @@
+package layout
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// messages runs cmd and returns every message it produces, unpacking batches
+func messages(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, messages(c)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+func TestListKeyMessages(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       string
+		activated bool
+		selected  bool
+	}{
+		{"enter activates without selecting", "enter", true, false},
+		{"space selects without activating", "space", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewListLayout("", 0)
+			l.SetID("things")
+			l.SetSize(20, 10)
+			l.AddItems([]string{"a", "b"})
+
+			_, cmd := l.Update(key(tt.key))
+			activated, selected := false, false
+			for _, msg := range messages(cmd) {
+				switch msg := msg.(type) {
+				case ListItemActivatedMsg:
+					activated = msg.ID == "things" && msg.Item.Value == "a"
+				case ListSelectionChangedMsg:
+					selected = len(msg.Selected) == 1
+				}
+			}
+			if activated != tt.activated || selected != tt.selected {
+				t.Errorf("activated %v, selected %v; want %v, %v", activated, selected, tt.activated, tt.selected)
+			}
+			if l.items[0].Selected != tt.selected {
+				t.Errorf("item selected = %v, want %v", l.items[0].Selected, tt.selected)
+			}
+		})
+	}
+}
+
+func TestListSelectionMessagesOnlyOnChange(t *testing.T) {
+	tests := []struct {
+		name  string
+		max   int
+		items []string
+		setup []string // Keys pressed before the one under test
+		key   string
+		want  bool
+	}{
+		{"toggle", 0, []string{"a", "b"}, nil, "space", true},
+		{"toggle at the limit", 1, []string{"a", "b"}, []string{"space", "down"}, "space", true},
+		{"toggle over the limit", 2, []string{"a", "b", "c"}, []string{"space", "down", "space", "down"}, "space", false},
+		{"toggle in an empty list", 0, nil, nil, "space", false},
+		{"toggle with nothing shown", 0, []string{"a"}, []string{"/", "z", "enter"}, "space", false},
+		{"select all", 0, []string{"a", "b"}, nil, "a", true},
+		{"select all again", 0, []string{"a", "b"}, []string{"a"}, "a", false},
+		{"select all over the limit", 1, []string{"a", "b"}, nil, "a", false},
+		{"select none with nothing selected", 0, []string{"a", "b"}, nil, "A", false},
+		{"invert", 0, []string{"a", "b"}, nil, "i", true},
+		{"invert with nothing shown", 0, []string{"a"}, []string{"/", "z", "enter"}, "i", false},
+		{"invert over the limit", 1, []string{"a", "b"}, nil, "i", false},
+		{"extend", 0, []string{"a", "b"}, nil, "J", true},
+		{"extend over the limit", 1, []string{"a", "b"}, []string{"space"}, "J", false},
+		{"visual on a selected item", 0, []string{"a", "b"}, []string{"space"}, "v", false},
+		{"visual cancel without moving", 0, []string{"a", "b"}, []string{"space", "v"}, "esc", false},
+		{"visual cancel after moving", 0, []string{"a", "b"}, []string{"v", "down"}, "esc", true},
+		{"pattern", 0, []string{"a", "b"}, []string{"*", "a"}, "enter", true},
+		{"pattern without matches", 0, []string{"a", "b"}, []string{"*", "z"}, "enter", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewListLayout("", tt.max)
+			l.SetSize(20, 10)
+			l.AddItems(tt.items)
+			press(l, tt.setup...)
+
+			_, cmd := l.Update(key(tt.key))
+			got := false
+			for _, msg := range messages(cmd) {
+				switch msg.(type) {
+				case ListSelectionChangedMsg, eventMsg:
+					got = true
+				}
+			}
+			if got != tt.want {
+				t.Errorf("selection change announced = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package layout
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/cactircool/bitwave/bindings"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Range selection: shift+arrows and visual mode both select everything
+// between an anchor and the cursor, on top of the selection from before
+// the range started
+
+// startRange anchors a range at the cursor, unless one is already going
+func (l *ListLayout) startRange() {
+	if l.anchor >= 0 {
+		return
+	}
+	l.anchor = l.cursor
+	l.base = make([]bool, len(l.items))
+	for i, item := range l.items {
+		l.base[i] = item.Selected
+	}
+}
+
+// endRange keeps the range's selection and forgets the anchor
+func (l *ListLayout) endRange() {
+	l.anchor = -1
+	l.base = nil
+}
+
+// cancelRange puts the selection back to how it was before the range and
+// reports whether that changed it
+func (l *ListLayout) cancelRange() bool {
+	changed := false
+	if l.anchor >= 0 && len(l.base) == len(l.items) {
+		before := l.selection()
+		for i := range l.items {
+			l.items[i].Selected = l.base[i]
+		}
+		changed = l.selectionChanged(before)
+	}
+	l.endRange()
+	return changed
+}
+
+// applyRange selects the shown items from the anchor to the cursor,
+// stopping at maxSelections, and reports whether the selection changed
+func (l *ListLayout) applyRange(shown []fuzzyResult) bool {
+	if l.anchor < 0 || len(l.base) != len(l.items) {
+		return false
+	}
+	from, to := -1, l.cursorPosition(shown)
+	for pos, result := range shown {
+		if result.index == l.anchor {
+			from = pos
+		}
+	}
+	if from < 0 || to < 0 {
+		return false
+	}
+
+	before := l.selection()
+	for i := range l.items {
+		l.items[i].Selected = l.base[i]
+	}
+	step := 1
+	if to < from {
+		step = -1
+	}
+	for pos := from; ; pos += step {
+		if !l.selectItem(shown[pos].index) {
+			break
+		}
+		if pos == to {
+			break
+		}
+	}
+	return l.selectionChanged(before)
+}
+
+// selection returns whether each item is selected
+func (l *ListLayout) selection() []bool {
+	selected := make([]bool, len(l.items))
+	for i, item := range l.items {
+		selected[i] = item.Selected
+	}
+	return selected
+}
+
+// selectionChanged reports whether the selection differs from before
+func (l *ListLayout) selectionChanged(before []bool) bool {
+	if len(before) != len(l.items) {
+		return true
+	}
+	for i, item := range l.items {
+		if item.Selected != before[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// setShownSelected selects or deselects every shown item and reports
+// whether any of them changed
+func (l *ListLayout) setShownSelected(shown []fuzzyResult, selected bool) bool {
+	changed := false
+	for _, result := range shown {
+		item := &l.items[result.index]
+		changed = changed || item.Selected != selected
+		item.Selected = selected
+	}
+	return changed
+}
+
+// selectedCount returns how many items are selected
+func (l *ListLayout) selectedCount() int {
+	count := 0
+	for _, item := range l.items {
+		if item.Selected {
+			count++
+		}
+	}
+	return count
+}
+
+// selectItem selects the item at index, or sets the limit message and
+// returns false if that would go over maxSelections
+func (l *ListLayout) selectItem(index int) bool {
+	if l.items[index].Selected {
+		return true
+	}
+	if l.maxSelections > 0 && l.selectedCount() >= l.maxSelections {
+		l.limitReached()
+		return false
+	}
+	l.items[index].Selected = true
+	return true
+}
+
+func (l *ListLayout) limitReached() {
+	l.message = fmt.Sprintf("Selection limit reached: at most %d", l.maxSelections)
+}
+
+// InvertSelection flips the selection of every shown item and reports
+// whether there were any
+// Nothing changes if the result would go over maxSelections
+func (l *ListLayout) InvertSelection() bool {
+	shown := l.shown()
+	count := l.selectedCount()
+	for _, result := range shown {
+		if l.items[result.index].Selected {
+			count--
+		} else {
+			count++
+		}
+	}
+	if l.maxSelections > 0 && count > l.maxSelections {
+		l.limitReached()
+		return false
+	}
+	for _, result := range shown {
+		l.items[result.index].Selected = !l.items[result.index].Selected
+	}
+	return len(shown) > 0
+}
+
+// SelectMatching selects the shown items whose values match pattern, up to
+// maxSelections, and returns how many it selected
+// pattern is a glob like "*.go" if it has any of *?[, otherwise a substring;
+// either way case is ignored
+func (l *ListLayout) SelectMatching(pattern string) int {
+	lower := strings.ToLower(pattern)
+	glob := strings.ContainsAny(lower, "*?[")
+
+	selected := 0
+	for _, result := range l.shown() {
+		item := &l.items[result.index]
+		value := strings.ToLower(item.Value)
+		var ok bool
+		if glob {
+			ok, _ = path.Match(lower, value)
+		} else {
+			ok = strings.Contains(value, lower)
+		}
+		if !ok || item.Selected {
+			continue
+		}
+		if !l.selectItem(result.index) {
+			break
+		}
+		selected++
+	}
+	if selected == 0 && l.message == "" {
+		l.message = fmt.Sprintf("No items match %q", pattern)
+	}
+	return selected
+}
+
+// updateVisual handles a key in visual mode
+func (l *ListLayout) updateVisual(msg tea.Msg) tea.Cmd {
+	shown := l.shown()
+	pos := l.cursorPosition(shown)
+	action, _ := l.visualKeymap.ActionFor(msg)
+	switch action {
+	case bindings.Up:
+		l.moveTo(shown, pos-1)
+	case bindings.Down:
+		l.moveTo(shown, pos+1)
+	case bindings.Top:
+		l.moveTo(shown, 0)
+	case bindings.Bottom:
+		l.moveTo(shown, len(shown)-1)
+	case bindings.Visual:
+		l.visual = false
+		l.endRange()
+		return nil
+	case bindings.Cancel:
+		l.visual = false
+		if l.cancelRange() {
+			return l.publishSelection()
+		}
+		return nil
+	default:
+		return nil
+	}
+	if l.applyRange(shown) {
+		return l.publishSelection()
+	}
+	return nil
+}
+
+// updatePattern handles a key while typing a select-matching pattern
+func (l *ListLayout) updatePattern(msg tea.Msg) tea.Cmd {
+	action, _ := l.patternKeymap.ActionFor(msg)
+	switch action {
+	case bindings.Run:
+		l.selectingPattern = false
+		l.patternInput.Blur()
+		if l.patternInput.Value() == "" {
+			return nil
+		}
+		if l.SelectMatching(l.patternInput.Value()) > 0 {
+			return l.publishSelection()
+		}
+		return nil
+	case bindings.Cancel:
+		l.selectingPattern = false
+		l.patternInput.Blur()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	l.patternInput, cmd = l.patternInput.Update(msg)
+	return cmd
+}
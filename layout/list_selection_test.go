@@ -0,0 +1,74 @@
+package layout
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func selectedValues(l *ListLayout) []string {
+	values := []string{}
+	for _, item := range l.GetSelectedItems() {
+		values = append(values, item.Value)
+	}
+	return values
+}
+
+func TestListSelection(t *testing.T) {
+	files := []string{"main.go", "list.go", "README.md", "notes.txt", "Makefile"}
+	tests := []struct {
+		name    string
+		items   []string
+		max     int
+		filter  string
+		keys    []string
+		want    []string
+		message string // Substring of the message left shown, "" for none
+	}{
+		{"extend down", files, 0, "", []string{"J", "J"}, []string{"main.go", "list.go", "README.md"}, ""},
+		{"extend back over the anchor", files, 0, "", []string{"j", "j", "K", "K", "K"}, []string{"main.go", "list.go", "README.md"}, ""},
+		{"extend keeps earlier selection", files, 0, "", []string{"G", "space", "top", "J"}, []string{"main.go", "list.go", "Makefile"}, ""},
+		{"extend stops at limit", files, 2, "", []string{"J", "J", "J"}, []string{"main.go", "list.go"}, "Selection limit"},
+		{"extend in empty list", nil, 0, "", []string{"J", "K"}, []string{}, ""},
+		{"visual keep", files, 0, "", []string{"v", "j", "j", "v"}, []string{"main.go", "list.go", "README.md"}, ""},
+		{"visual cancel", files, 0, "", []string{"space", "v", "j", "j", "esc"}, []string{"main.go"}, ""},
+		{"visual over filter", files, 0, "go", []string{"v", "j", "v"}, []string{"main.go", "list.go"}, ""},
+		{"invert", files, 0, "", []string{"space", "i"}, []string{"list.go", "README.md", "notes.txt", "Makefile"}, ""},
+		{"invert only shown", files, 0, "go", []string{"i"}, []string{"main.go", "list.go"}, ""},
+		{"invert over limit", files, 2, "", []string{"i"}, []string{}, "Selection limit"},
+		{"invert empty list", nil, 0, "", []string{"i"}, []string{}, ""},
+		{"pattern glob", files, 0, "", []string{"*", "*", ".", "g", "o", "enter"}, []string{"main.go", "list.go"}, ""},
+		{"pattern substring ignores case", files, 0, "", []string{"*", "M", "E", "enter"}, []string{"README.md"}, ""},
+		{"pattern up to limit", files, 1, "", []string{"*", "m", "a", "enter"}, []string{"main.go"}, "Selection limit"},
+		{"pattern without matches", files, 0, "", []string{"*", "X", "Y", "enter"}, []string{}, `"XY"`},
+		{"pattern over filtered list", files, 0, "ma", []string{"*", "e", "enter"}, []string{"Makefile"}, ""},
+		{"pattern cancelled", files, 0, "", []string{"*", "m", "esc"}, []string{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewListLayout("", tt.max)
+			l.SetSize(40, 20)
+			l.AddItems(tt.items)
+			l.SetFilter(tt.filter)
+			for _, name := range tt.keys {
+				if name == "top" {
+					// "g g" is a sequence, which only the root matches
+					l.moveTo(l.shown(), 0)
+					continue
+				}
+				press(l, name)
+			}
+
+			if got := selectedValues(l); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selected = %q, want %q", got, tt.want)
+			}
+			if tt.message != "" && !strings.Contains(l.message, tt.message) {
+				t.Errorf("message = %q, want one containing %q", l.message, tt.message)
+			}
+			if tt.message == "" && l.message != "" {
+				t.Errorf("unexpected message %q", l.message)
+			}
+			l.View()
+		})
+	}
+}
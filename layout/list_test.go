@@ -0,0 +1,168 @@
+package layout
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// itemValues returns n values, "item 00" onwards
+func itemValues(n int) []string {
+	values := make([]string, n)
+	for i := range values {
+		values[i] = fmt.Sprintf("item %02d", i)
+	}
+	return values
+}
+
+// shrinkWhile presses keys on model, then cuts its bound source down to keep,
+// like another goroutine changing the source mid-interaction
+// The model only sees the change on its next Update
+func shrinkWhile[T any](model tea.Model, source *ObservableList[T], keys []string, keep ...T) {
+	press(model, keys...)
+	source.Replace(keep)
+}
+
+func TestListFilter(t *testing.T) {
+	fruits := []string{"apple", "banana", "cherry", "grape", "pineapple", "apricot"}
+	tests := []struct {
+		name       string
+		items      []string
+		max        int
+		keys       []string
+		wantFilter string
+		wantCursor string // Value under the cursor, "" for none
+		wantShown  int
+		selected   []string
+	}{
+		{"typing filters", fruits, 0, []string{"/", "a", "p"}, "ap", "apple", 4, []string{}},
+		{"enter keeps the filter", fruits, 0, []string{"/", "c", "h", "enter", "space"}, "ch", "cherry", 1, []string{"cherry"}},
+		{"esc clears the filter", fruits, 0, []string{"/", "c", "h", "esc"}, "", "cherry", 6, []string{}},
+		{"esc after enter clears", fruits, 0, []string{"/", "c", "h", "enter", "esc"}, "", "cherry", 6, []string{}},
+		{"move within matches", fruits, 0, []string{"/", "a", "p", "down", "down", "enter"}, "ap", "grape", 4, []string{}},
+		{"move past the last match", fruits, 0, []string{"/", "a", "p", "enter", "j", "j", "j", "j", "j"}, "ap", "pineapple", 4, []string{}},
+		{"no matches", fruits, 0, []string{"/", "z", "z", "enter", "j", "k", "G", "space", "enter", "a", "i"}, "zz", "", 0, []string{}},
+		{"empty list", nil, 0, []string{"/", "a", "enter", "j", "space", "a"}, "a", "", 0, []string{}},
+		{"selections on hidden items are kept", fruits, 0, []string{"space", "/", "c", "h", "enter", "space"}, "ch", "cherry", 1, []string{"apple", "cherry"}},
+		{"select all selects only matches", fruits, 0, []string{"/", "a", "p", "r", "enter", "a"}, "apr", "apricot", 1, []string{"apricot"}},
+		{"select all over the limit", fruits, 2, []string{"/", "a", "p", "enter", "a"}, "ap", "apple", 4, []string{}},
+		{"select all within the limit", fruits, 2, []string{"/", "c", "h", "enter", "a"}, "ch", "cherry", 1, []string{"cherry"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewListLayout("Fruit", tt.max)
+			l.SetSize(30, 8)
+			l.AddItems(tt.items)
+			press(l, tt.keys...)
+
+			if l.Filter() != tt.wantFilter {
+				t.Errorf("filter = %q, want %q", l.Filter(), tt.wantFilter)
+			}
+			shown := l.shown()
+			if len(shown) != tt.wantShown {
+				t.Errorf("%d items shown, want %d", len(shown), tt.wantShown)
+			}
+			cursor := ""
+			if pos := l.cursorPosition(shown); pos >= 0 {
+				cursor = l.items[l.cursor].Value
+			}
+			if cursor != tt.wantCursor {
+				t.Errorf("cursor on %q, want %q", cursor, tt.wantCursor)
+			}
+			if got := selectedValues(l); !reflect.DeepEqual(got, tt.selected) {
+				t.Errorf("selected = %q, want %q", got, tt.selected)
+			}
+			if view := l.View(); tt.wantCursor != "" && !strings.Contains(view, tt.wantCursor) {
+				t.Errorf("cursor's item %q not on screen:\n%s", tt.wantCursor, view)
+			}
+		})
+	}
+}
+
+func TestListScrollKeepsCursorVisible(t *testing.T) {
+	items := itemValues(50)
+	tests := []struct {
+		name   string
+		height int
+		filter string
+		keys   []string
+		want   string // "" for the last item shown
+	}{
+		{"down a page", 10, "", []string{"j", "j", "j", "j", "j", "j", "j", "j"}, "item 08"},
+		{"bottom", 10, "", []string{"G"}, "item 49"},
+		{"bottom then up", 10, "", []string{"G", "k", "k", "k", "k", "k", "k", "k"}, "item 42"},
+		{"filtered bottom", 10, "4", []string{"G"}, ""},
+		{"taller than the list", 100, "", []string{"G"}, "item 49"},
+		{"no room", 1, "", []string{"G"}, "item 49"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewListLayout("Items", 0)
+			l.SetSize(30, tt.height)
+			l.AddItems(items)
+			l.SetFilter(tt.filter)
+			press(l, tt.keys...)
+
+			if tt.want == "" {
+				shown := l.shown()
+				tt.want = l.items[shown[len(shown)-1].index].Value
+			}
+			if got := l.items[l.cursor].Value; got != tt.want {
+				t.Fatalf("cursor on %q, want %q", got, tt.want)
+			}
+			if view := l.View(); !strings.Contains(view, tt.want) {
+				t.Errorf("cursor's item %q not on screen:\n%s", tt.want, view)
+			}
+		})
+	}
+}
+
+func TestListFilterShrinkingSource(t *testing.T) {
+	tests := []struct {
+		name  string
+		keep  []string
+		shown int
+	}{
+		{"cursor's match removed", []string{"alpha", "beta", "zeta"}, 3},
+		{"no matches left", []string{"xyz"}, 0},
+		{"source emptied", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewObservableList("alpha", "beta", "gamma", "delta", "zeta")
+			l := NewListLayout("", 0)
+			l.SetSize(30, 10)
+			l.BindItems(source)
+			shrinkWhile(l, source, []string{"/", "a", "enter", "G"}, tt.keep...)
+			press(l, "k", "j")
+
+			if l.Filter() != "a" {
+				t.Errorf("filter = %q, want it kept", l.Filter())
+			}
+			shown := l.shown()
+			if len(shown) != tt.shown {
+				t.Fatalf("%d items shown, want %d", len(shown), tt.shown)
+			}
+			if tt.shown > 0 && l.cursorPosition(shown) < 0 {
+				t.Errorf("cursor on hidden item %d", l.cursor)
+			}
+		})
+	}
+}
+
+func TestListAddItemWritesThrough(t *testing.T) {
+	source := NewObservableList("a")
+	l := NewListLayout("", 0)
+	l.BindItems(source)
+	l.AddItem("b", 2)
+
+	if got := source.Items(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("source = %q, want [a b]", got)
+	}
+	if l.items[1].Data != 2 {
+		t.Errorf("added item lost its data: %v", l.items[1].Data)
+	}
+}
@@ -0,0 +1,599 @@
+package layout
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/cactircool/bitwave/bindings"
+	"github.com/cactircool/bitwave/theme"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DefaultLogCapacity is how many lines a LogLayout keeps unless told otherwise
+const DefaultLogCapacity = 10000
+
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return "info"
+}
+
+// tag is the short label shown in front of a line
+func (l LogLevel) tag() string {
+	switch l {
+	case LevelDebug:
+		return "DBG"
+	case LevelWarn:
+		return "WRN"
+	case LevelError:
+		return "ERR"
+	}
+	return "INF"
+}
+
+type LogLine struct {
+	Level LogLevel
+	Text  string // May contain ANSI colors
+}
+
+// LogAppendMsg adds lines to the LogLayout it's delivered to, e.g. with SendTo
+type LogAppendMsg struct {
+	Lines []LogLine
+}
+
+// logEntry is a stored line with its position in everything ever appended,
+// which stays put as old lines fall out of the buffer
+type logEntry struct {
+	LogLine
+	seq uint64
+}
+
+// LogLayout shows a stream of lines, following the newest until the user
+// scrolls up
+// Lines can be appended from any goroutine with Append or by writing to it
+// as an io.Writer; the UI picks them up on the next ObservableChangedMsg
+type LogLayout struct {
+	Identity
+	DisabledState
+	width  int
+	height int
+
+	// Written from other goroutines, moved into the buffer by drain
+	mu      sync.Mutex
+	pending []LogLine
+	partial string // Unterminated line from Write
+
+	// Ring buffer of the newest lines
+	entries []logEntry
+	start   int
+	count   int
+	next    uint64 // seq of the next appended line
+
+	follow     bool
+	top        uint64 // seq of the first line shown while not following
+	minLevel   LogLevel
+	showLevels bool
+
+	query     string
+	match     uint64 // seq of the current search match
+	hasMatch  bool
+	searching bool
+	previous  string // Query to restore if the search is cancelled
+	input     textinput.Model
+
+	keymap       *bindings.Keymap
+	searchKeymap *bindings.Keymap
+}
+
+// NewLogLayout keeps the newest capacity lines, DefaultLogCapacity if capacity <= 0
+func NewLogLayout(capacity int) *LogLayout {
+	if capacity <= 0 {
+		capacity = DefaultLogCapacity
+	}
+	input := textinput.New()
+	input.Prompt = "/"
+	return &LogLayout{
+		entries:      make([]logEntry, capacity),
+		follow:       true,
+		showLevels:   true,
+		input:        input,
+		keymap:       bindings.Log.Clone(),
+		searchKeymap: bindings.LogSearch.Clone(),
+	}
+}
+
+// Append adds lines to the log; it's safe to call from any goroutine
+func (l *LogLayout) Append(lines ...LogLine) {
+	l.mu.Lock()
+	l.pending = append(l.pending, lines...)
+	l.mu.Unlock()
+	notifyChanged()
+}
+
+// Write appends each complete line of p, guessing its level from its text
+// Safe to call from any goroutine, e.g. as the output of a command
+func (l *LogLayout) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	lines := strings.Split(l.partial+string(p), "\n")
+	l.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		line = strings.TrimSuffix(line, "\r")
+		l.pending = append(l.pending, LogLine{Level: DetectLogLevel(line), Text: line})
+	}
+	l.mu.Unlock()
+	if len(lines) > 1 {
+		notifyChanged()
+	}
+	return len(p), nil
+}
+
+// DetectLogLevel guesses the level of a line from a level word near its start,
+// like "ERROR", "[warn]" or "level=debug"
+func DetectLogLevel(text string) LogLevel {
+	fields := strings.FieldsFunc(ansi.Strip(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, field := range fields[:min(len(fields), 4)] {
+		switch strings.ToUpper(field) {
+		case "ERROR", "ERR", "FATAL", "PANIC", "CRIT", "CRITICAL":
+			return LevelError
+		case "WARN", "WARNING", "WRN":
+			return LevelWarn
+		case "DEBUG", "DBG", "TRACE":
+			return LevelDebug
+		case "INFO", "INF":
+			return LevelInfo
+		}
+	}
+	return LevelInfo
+}
+
+// Clear drops every line
+func (l *LogLayout) Clear() {
+	l.drain()
+	l.start, l.count = 0, 0
+	l.hasMatch = false
+	l.follow = true
+}
+
+// Lines returns the buffered lines, oldest first
+func (l *LogLayout) Lines() []LogLine {
+	l.drain()
+	lines := make([]LogLine, l.count)
+	for i := range lines {
+		lines[i] = l.at(i).LogLine
+	}
+	return lines
+}
+
+// drain moves lines appended from other goroutines into the buffer
+func (l *LogLayout) drain() {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+	l.push(pending...)
+}
+
+func (l *LogLayout) push(lines ...LogLine) {
+	capacity := len(l.entries)
+	for _, line := range lines {
+		entry := logEntry{LogLine: line, seq: l.next}
+		l.next++
+		if l.count < capacity {
+			l.entries[(l.start+l.count)%capacity] = entry
+			l.count++
+		} else {
+			l.entries[l.start] = entry
+			l.start = (l.start + 1) % capacity
+		}
+	}
+}
+
+func (l *LogLayout) at(i int) logEntry {
+	return l.entries[(l.start+i)%len(l.entries)]
+}
+
+// SetMinLevel hides lines below level
+func (l *LogLayout) SetMinLevel(level LogLevel) {
+	l.minLevel = level
+}
+
+// SetShowLevels turns the level tag in front of each line on or off
+func (l *LogLayout) SetShowLevels(show bool) {
+	l.showLevels = show
+}
+
+// SetFollow sticks the view to the newest line, or pauses it where it is
+func (l *LogLayout) SetFollow(follow bool) {
+	if !follow && l.follow {
+		visible := l.visible()
+		if first := l.first(visible); first < len(visible) {
+			l.top = visible[first].seq
+		}
+	}
+	l.follow = follow
+}
+
+// Following reports whether the view sticks to the newest line
+func (l *LogLayout) Following() bool {
+	return l.follow
+}
+
+// Search highlights lines containing query, ignoring case, and jumps to the
+// first one at or below the top of the view
+func (l *LogLayout) Search(query string) {
+	l.drain()
+	l.query = query
+	l.hasMatch = false
+	visible := l.visible()
+	if query == "" || len(visible) == 0 {
+		return
+	}
+	l.findMatch(visible, l.first(visible), 1, true)
+}
+
+// Keymap returns the log's keymap, which can be overridden per log
+func (l *LogLayout) Keymap() *bindings.Keymap {
+	return l.keymap
+}
+
+// SearchKeymap returns the keymap used while typing a search
+func (l *LogLayout) SearchKeymap() *bindings.Keymap {
+	return l.searchKeymap
+}
+
+// ActiveKeymap returns the search keymap while searching, the log keymap otherwise
+func (l *LogLayout) ActiveKeymap() *bindings.Keymap {
+	if l.searching {
+		return l.searchKeymap
+	}
+	return l.keymap
+}
+
+func (l *LogLayout) SetKeymaps(keymap, searchKeymap *bindings.Keymap) {
+	l.keymap = keymap
+	l.searchKeymap = searchKeymap
+}
+
+// visible returns the buffered lines that pass the level filter
+func (l *LogLayout) visible() []logEntry {
+	visible := make([]logEntry, 0, l.count)
+	for i := 0; i < l.count; i++ {
+		if entry := l.at(i); entry.Level >= l.minLevel {
+			visible = append(visible, entry)
+		}
+	}
+	return visible
+}
+
+// bodyHeight is the room for lines above the status line
+func (l *LogLayout) bodyHeight() int {
+	return max(l.height-1, 0)
+}
+
+// first returns the index into visible of the top line shown
+func (l *LogLayout) first(visible []logEntry) int {
+	last := max(len(visible)-l.bodyHeight(), 0)
+	if l.follow {
+		return last
+	}
+	for i, entry := range visible {
+		if entry.seq >= l.top {
+			return min(i, last)
+		}
+	}
+	return last
+}
+
+// scrollTo shows visible[first] at the top, following again at the bottom
+func (l *LogLayout) scrollTo(visible []logEntry, first int) {
+	last := max(len(visible)-l.bodyHeight(), 0)
+	first = max(min(first, last), 0)
+	l.follow = first == last
+	if first < len(visible) {
+		l.top = visible[first].seq
+	}
+}
+
+func (l *LogLayout) matches(entry logEntry) bool {
+	return l.query != "" && strings.Contains(strings.ToLower(ansi.Strip(entry.Text)), strings.ToLower(l.query))
+}
+
+// findMatch moves to the next match from visible[from] in direction step,
+// wrapping around, and scrolls it into view
+func (l *LogLayout) findMatch(visible []logEntry, from, step int, inclusive bool) {
+	n := len(visible)
+	if n == 0 || l.query == "" {
+		return
+	}
+	offset := 1
+	if inclusive {
+		offset = 0
+	}
+	for k := 0; k < n; k++ {
+		i := ((from+step*(k+offset))%n + n) % n
+		if l.matches(visible[i]) {
+			l.match, l.hasMatch = visible[i].seq, true
+			l.scrollTo(visible, i-l.bodyHeight()/2)
+			return
+		}
+	}
+	l.hasMatch = false
+}
+
+// matchIndex returns the index into visible of the current match, or -1
+func (l *LogLayout) matchIndex(visible []logEntry) int {
+	if !l.hasMatch {
+		return -1
+	}
+	for i, entry := range visible {
+		if entry.seq == l.match {
+			return i
+		}
+	}
+	return -1
+}
+
+func (l *LogLayout) SetSize(width, height int) {
+	l.width = width
+	l.height = height
+	l.input.Width = max(width-2, 1)
+}
+
+func (l *LogLayout) GetFocusState() FocusState {
+	if l.IsDisabled() {
+		return Disabled
+	}
+	return Focusable
+}
+
+func (l *LogLayout) OnFocus(baseStyle lipgloss.Style) (lipgloss.Style, tea.Cmd) {
+	// Focus is drawn by the layout's FocusPolicy
+	return baseStyle, nil
+}
+
+func (l *LogLayout) OnBlur() {
+	l.stopSearching(false)
+}
+
+// Describe summarizes the log for the linear view, as of the last Update
+func (l *LogLayout) Describe() string {
+	visible := l.visible()
+	description := fmt.Sprintf("Log, %d lines", len(visible))
+	if l.follow {
+		description += ", following"
+	} else {
+		description += fmt.Sprintf(", paused at line %d", l.first(visible)+1)
+	}
+	if l.minLevel > LevelDebug {
+		description += ", " + l.minLevel.String() + " and above"
+	}
+	if l.searching {
+		description += ", searching"
+	}
+	return description
+}
+
+// CapturesKey claims every key while a search is being typed
+func (l *LogLayout) CapturesKey(key tea.KeyMsg) bool {
+	return l.searching
+}
+
+// IsActive reports whether a search is being typed
+func (l *LogLayout) IsActive() bool {
+	return l.searching
+}
+
+func (l *LogLayout) stopSearching(keep bool) {
+	if !l.searching {
+		return
+	}
+	l.searching = false
+	l.input.Blur()
+	if !keep {
+		l.Search(l.previous)
+	}
+}
+
+func (l *LogLayout) Init() tea.Cmd {
+	return nil
+}
+
+func (l *LogLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch v := msg.(type) {
+	case ObservableChangedMsg:
+		l.drain()
+		return l, nil
+	case LogAppendMsg:
+		l.drain()
+		l.push(v.Lines...)
+		return l, nil
+	}
+	if !isKeyInput(msg) {
+		return l, nil
+	}
+
+	if l.searching {
+		action, _ := l.searchKeymap.ActionFor(msg)
+		switch action {
+		case bindings.Run:
+			l.stopSearching(true)
+			return l, nil
+		case bindings.Cancel:
+			l.stopSearching(false)
+			return l, nil
+		}
+		// Search as you type
+		var cmd tea.Cmd
+		l.input, cmd = l.input.Update(msg)
+		if l.input.Value() != l.query {
+			l.Search(l.input.Value())
+		}
+		return l, cmd
+	}
+
+	visible := l.visible()
+	first := l.first(visible)
+	page := max(l.bodyHeight(), 1)
+	action, _ := l.keymap.ActionFor(msg)
+	switch action {
+	case bindings.Up:
+		l.scrollTo(visible, first-1)
+	case bindings.Down:
+		l.scrollTo(visible, first+1)
+	case bindings.PageUp:
+		l.scrollTo(visible, first-page)
+	case bindings.PageDown:
+		l.scrollTo(visible, first+page)
+	case bindings.Top:
+		l.scrollTo(visible, 0)
+	case bindings.Bottom:
+		l.scrollTo(visible, len(visible))
+	case bindings.Follow:
+		l.SetFollow(!l.follow)
+	case bindings.Search:
+		l.searching = true
+		l.previous = l.query
+		l.input.SetValue("")
+		return l, l.input.Focus()
+	case bindings.NextMatch, bindings.PreviousMatch:
+		step := 1
+		if action == bindings.PreviousMatch {
+			step = -1
+		}
+		from := l.matchIndex(visible)
+		inclusive := from < 0
+		if inclusive {
+			from = first
+		}
+		l.findMatch(visible, from, step, inclusive)
+	case bindings.CycleLevel:
+		l.minLevel = (l.minLevel + 1) % (LevelError + 1)
+	}
+	return l, nil
+}
+
+// csiNonStyle matches control sequences other than colors and styles,
+// which would move the cursor or clear the screen
+var csiNonStyle = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-ln-z@]`)
+
+// sanitizeLine keeps a line's colors but drops anything that would
+// disturb the layout
+func sanitizeLine(text string) string {
+	// A carriage return redraws the line, as progress bars do
+	if i := strings.LastIndexByte(text, '\r'); i >= 0 {
+		text = text[i+1:]
+	}
+	text = csiNonStyle.ReplaceAllString(text, "")
+	return strings.ReplaceAll(text, "\t", "    ")
+}
+
+func (l *LogLayout) renderLine(entry logEntry, current bool) string {
+	t := theme.Current()
+	prefix := ""
+	if l.showLevels {
+		levelStyle := lipgloss.NewStyle()
+		switch entry.Level {
+		case LevelDebug:
+			levelStyle = levelStyle.Foreground(t.Color(theme.Muted))
+		case LevelInfo:
+			levelStyle = levelStyle.Foreground(t.Color(theme.Primary))
+		case LevelWarn:
+			levelStyle = levelStyle.Foreground(t.Color(theme.Warning)).Bold(true)
+		case LevelError:
+			levelStyle = levelStyle.Foreground(t.Color(theme.Error)).Bold(true)
+		}
+		prefix = levelStyle.Render(entry.Level.tag()) + " "
+	}
+	width := max(l.width-textWidth(prefix), 0)
+	text := sanitizeLine(entry.Text)
+
+	if !l.matches(entry) {
+		// Reset so an unterminated color doesn't bleed into the next line
+		return prefix + DefaultTruncation.Truncate(text, width) + ansi.ResetStyle
+	}
+
+	// Matched lines lose their own colors so the matches stand out
+	plain := ansi.Strip(text)
+	positions := substringPositions(plain, l.query, true)
+	base := lipgloss.NewStyle()
+	if current {
+		base = base.Background(t.Color(theme.Selection)).Foreground(t.Color(theme.Inverse))
+	}
+	line := highlightMatches(plain, positions, base, lipgloss.NewStyle().Reverse(true).Bold(true))
+	return prefix + DefaultTruncation.Truncate(line, width)
+}
+
+func (l *LogLayout) statusLine() string {
+	if l.searching {
+		return l.input.View()
+	}
+
+	t := theme.Current()
+	parts := []string{}
+	if l.follow {
+		parts = append(parts, "following")
+	} else {
+		parts = append(parts, "paused")
+	}
+	if l.minLevel > LevelDebug {
+		parts = append(parts, l.minLevel.String()+"+")
+	}
+	if l.query != "" {
+		visible := l.visible()
+		total, current := 0, 0
+		match := l.matchIndex(visible)
+		for i, entry := range visible {
+			if l.matches(entry) {
+				total++
+				if i == match {
+					current = total
+				}
+			}
+		}
+		parts = append(parts, fmt.Sprintf("/%s %d/%d", l.query, current, total))
+	}
+	return lipgloss.NewStyle().Foreground(t.Color(theme.Muted)).Render(
+		DefaultTruncation.Truncate(strings.Join(parts, " · "), l.width))
+}
+
+func (l *LogLayout) View() string {
+	visible := l.visible()
+	first := l.first(visible)
+	end := min(first+l.bodyHeight(), len(visible))
+	match := l.matchIndex(visible)
+
+	lines := make([]string, 0, l.height)
+	for i := first; i < end; i++ {
+		lines = append(lines, l.renderLine(visible[i], i == match))
+	}
+	for len(lines) < l.bodyHeight() {
+		lines = append(lines, "")
+	}
+	if l.height > 0 {
+		lines = append(lines, l.statusLine())
+	}
+	return lipgloss.NewStyle().Width(l.width).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,40 @@
+package layout
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLogDrainsOnlyInUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		append func(l *LogLayout)
+		msg    tea.Msg // Delivered after the append
+		want   int     // Lines shown after msg
+	}{
+		{"append then change", func(l *LogLayout) { l.Append(LogLine{Text: "one"}, LogLine{Text: "two"}) }, ObservableChangedMsg{}, 2},
+		{"write then change", func(l *LogLayout) { l.Write([]byte("one\ntwo\npartial")) }, ObservableChangedMsg{}, 2},
+		{"append message", func(l *LogLayout) { l.Append(LogLine{Text: "one"}) }, LogAppendMsg{Lines: []LogLine{{Text: "two"}}}, 2},
+		{"unrelated message", func(l *LogLayout) { l.Append(LogLine{Text: "one"}) }, tea.WindowSizeMsg{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLogLayout(10)
+			l.SetSize(40, 10)
+			tt.append(l)
+
+			// Rendering shows what the last Update saw
+			l.View()
+			if got := l.Describe(); !strings.HasPrefix(got, "Log, 0 lines") {
+				t.Errorf("describe before Update = %q, want no lines", got)
+			}
+
+			l.Update(tt.msg)
+			if got := len(l.visible()); got != tt.want {
+				t.Errorf("%d lines after Update, want %d", got, tt.want)
+			}
+		})
+	}
+}
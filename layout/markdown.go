@@ -0,0 +1,578 @@
+package layout
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/cactircool/bitwave/bindings"
+	"github.com/cactircool/bitwave/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// MarkdownLayout renders Markdown, reflowed to its width and scrollable
+// while focused
+// Supported: ATX headings, paragraphs, bullet and numbered lists, fenced
+// and indented code, tables, block quotes, rules, and inline emphasis,
+// code and links
+type MarkdownLayout struct {
+	Identity
+	DisabledState
+	markdown string
+	view     *TextLayout // Holds the rendered text and does the scrolling
+
+	// What the current rendering was made for
+	renderedWidth int
+	renderedTheme *theme.Theme
+}
+
+func NewMarkdownLayout(markdown string) *MarkdownLayout {
+	view := NewTextView("")
+	view.SetMarkup(false)
+	view.SetWrap(WrapNone)
+	view.SetOverflow(OverflowScroll)
+	return &MarkdownLayout{markdown: markdown, view: view, renderedWidth: -1}
+}
+
+// Markdown returns the source text
+func (m *MarkdownLayout) Markdown() string {
+	return m.markdown
+}
+
+// SetMarkdown replaces the document, keeping the scroll position where possible
+func (m *MarkdownLayout) SetMarkdown(markdown string) {
+	m.markdown = markdown
+	m.render()
+}
+
+func (m *MarkdownLayout) render() {
+	m.renderedWidth = m.view.width
+	m.renderedTheme = theme.Current()
+	m.view.SetText(renderMarkdown(m.markdown, m.view.width, m.renderedTheme))
+}
+
+func (m *MarkdownLayout) ActiveKeymap() *bindings.Keymap {
+	return m.view.ActiveKeymap()
+}
+
+func (m *MarkdownLayout) SetSize(width, height int) {
+	m.view.SetSize(width, height)
+	if width != m.renderedWidth {
+		m.render()
+	}
+}
+
+func (m *MarkdownLayout) GetFocusState() FocusState {
+	if m.IsDisabled() {
+		return Disabled
+	}
+	return Focusable
+}
+
+func (m *MarkdownLayout) OnFocus(baseStyle lipgloss.Style) (lipgloss.Style, tea.Cmd) {
+	return baseStyle, nil
+}
+
+func (m *MarkdownLayout) OnBlur() {}
+
+func (m *MarkdownLayout) Init() tea.Cmd {
+	return nil
+}
+
+func (m *MarkdownLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if set, ok := msg.(SetTextMsg); ok {
+		m.SetMarkdown(set.Text)
+		return m, nil
+	}
+	if isKeyInput(msg) {
+		m.view.Update(msg)
+	}
+	return m, nil
+}
+
+func (m *MarkdownLayout) View() string {
+	if m.renderedTheme != theme.Current() {
+		m.render()
+	}
+	return m.view.View()
+}
+
+// renderMarkdown renders src as styled lines at most width cells wide
+func renderMarkdown(src string, width int, t *theme.Theme) string {
+	r := markdownRenderer{theme: t}
+	return strings.Join(r.blocks(strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n"), max(width, 1)), "\n")
+}
+
+type markdownRenderer struct {
+	theme *theme.Theme
+}
+
+// blocks renders a run of lines, separating blocks with a blank line
+func (r markdownRenderer) blocks(lines []string, width int) []string {
+	out := []string{}
+	add := func(block []string) {
+		if len(out) > 0 {
+			out = append(out, "")
+		}
+		out = append(out, block...)
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence := trimmed[:3]
+			code := []string{}
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			i++ // Closing fence
+			add(r.code(code, width))
+
+		case strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t"):
+			code := []string{}
+			for ; i < len(lines) && (strings.HasPrefix(lines[i], "    ") || strings.HasPrefix(lines[i], "\t") || strings.TrimSpace(lines[i]) == ""); i++ {
+				code = append(code, strings.TrimPrefix(strings.TrimPrefix(lines[i], "\t"), "    "))
+			}
+			for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+				code = code[:len(code)-1]
+			}
+			add(r.code(code, width))
+
+		case headingLevel(trimmed) > 0:
+			add(r.heading(trimmed, width))
+			i++
+
+		case isRule(trimmed):
+			add([]string{lipgloss.NewStyle().Foreground(r.theme.Color(theme.Muted)).Render(strings.Repeat("─", width))})
+			i++
+
+		case startsTable(lines, i):
+			rows := []string{}
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				rows = append(rows, lines[i])
+			}
+			add(r.table(rows, width))
+
+		case strings.HasPrefix(trimmed, ">"):
+			quoted := []string{}
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				inner := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(inner, " "))
+			}
+			add(r.quote(quoted, width))
+
+		case listMarker(line) != "":
+			items := []string{}
+			for ; i < len(lines); i++ {
+				l := lines[i]
+				if strings.TrimSpace(l) == "" {
+					// A blank line only continues the list if another item follows
+					if i+1 < len(lines) && listMarker(lines[i+1]) != "" {
+						continue
+					}
+					break
+				}
+				if listMarker(l) == "" && !strings.HasPrefix(l, " ") && len(items) > 0 {
+					break
+				}
+				items = append(items, l)
+			}
+			add(r.list(items, width))
+
+		default:
+			paragraph := []string{}
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != "" && !startsBlock(lines, i); i++ {
+				paragraph = append(paragraph, strings.TrimSpace(lines[i]))
+			}
+			if len(paragraph) == 0 {
+				// startsBlock disagreed with the cases above; take the line as text
+				paragraph = append(paragraph, trimmed)
+				i++
+			}
+			add(r.wrap(r.inline(strings.Join(paragraph, " ")), width))
+		}
+	}
+	return out
+}
+
+// startsBlock reports whether lines[i] begins something other than a paragraph
+func startsBlock(lines []string, i int) bool {
+	line := lines[i]
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") ||
+		headingLevel(trimmed) > 0 || isRule(trimmed) || strings.HasPrefix(trimmed, ">") ||
+		listMarker(line) != "" || startsTable(lines, i)
+}
+
+// startsTable reports whether lines[i] is a table's header row, which is
+// only known from the separator row under it
+func startsTable(lines []string, i int) bool {
+	return strings.HasPrefix(strings.TrimSpace(lines[i]), "|") && i+1 < len(lines) && isTableSeparator(lines[i+1])
+}
+
+func headingLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level < len(line) && line[level] != ' ' {
+		return 0
+	}
+	return level
+}
+
+func (r markdownRenderer) heading(line string, width int) []string {
+	level := headingLevel(line)
+	text := strings.TrimSpace(strings.TrimRight(line[level:], "# "))
+
+	style := lipgloss.NewStyle().Bold(true)
+	switch level {
+	case 1:
+		style = style.Foreground(r.theme.Color(theme.Title)).Underline(true)
+	case 2:
+		style = style.Foreground(r.theme.Color(theme.Title))
+	case 3:
+		style = style.Foreground(r.theme.Color(theme.Primary))
+	}
+	lines := r.wrap(r.inline(text), width)
+	for i, l := range lines {
+		lines[i] = style.Render(ansi.Strip(l))
+	}
+	return lines
+}
+
+// isRule reports whether line is a thematic break like "---" or "* * *"
+func isRule(line string) bool {
+	compact := strings.ReplaceAll(line, " ", "")
+	if len(compact) < 3 {
+		return false
+	}
+	return strings.Trim(compact, "-") == "" || strings.Trim(compact, "*") == "" || strings.Trim(compact, "_") == ""
+}
+
+func (r markdownRenderer) code(lines []string, width int) []string {
+	style := lipgloss.NewStyle().Foreground(r.theme.Color(theme.Primary))
+	bar := lipgloss.NewStyle().Foreground(r.theme.Color(theme.Muted)).Render("│ ")
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		out[i] = bar + style.Render(ansi.Truncate(line, width-2, "…"))
+	}
+	return out
+}
+
+func (r markdownRenderer) quote(lines []string, width int) []string {
+	bar := lipgloss.NewStyle().Foreground(r.theme.Color(theme.Muted)).Render("┃ ")
+	inner := r.blocks(lines, max(width-2, 1))
+	for i, line := range inner {
+		inner[i] = bar + line
+	}
+	return inner
+}
+
+// listMarker returns the bullet or number that starts a list item, or ""
+func listMarker(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(trimmed) >= 2 && strings.ContainsRune("-*+", rune(trimmed[0])) && trimmed[1] == ' ' {
+		return trimmed[:1]
+	}
+	digits := 0
+	for digits < len(trimmed) && unicode.IsDigit(rune(trimmed[digits])) {
+		digits++
+	}
+	if digits > 0 && digits+1 < len(trimmed) && (trimmed[digits] == '.' || trimmed[digits] == ')') && trimmed[digits+1] == ' ' {
+		return trimmed[:digits+1]
+	}
+	return ""
+}
+
+func (r markdownRenderer) list(lines []string, width int) []string {
+	type item struct {
+		indent int
+		marker string
+		text   string
+	}
+	items := []item{}
+	for _, line := range lines {
+		marker := listMarker(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if marker == "" {
+			// Continuation of the previous item
+			items[len(items)-1].text += " " + strings.TrimSpace(line)
+			continue
+		}
+		text := strings.TrimSpace(strings.TrimLeft(line, " ")[len(marker):])
+		items = append(items, item{indent: indent / 2, marker: marker, text: text})
+	}
+
+	bulletStyle := lipgloss.NewStyle().Foreground(r.theme.Color(theme.Primary))
+	out := []string{}
+	for _, it := range items {
+		bullet := "•"
+		if strings.ContainsAny(it.marker[len(it.marker)-1:], ".)") {
+			bullet = it.marker
+		}
+		// Task list items
+		if strings.HasPrefix(it.text, "[ ] ") {
+			bullet, it.text = "☐", it.text[4:]
+		} else if strings.HasPrefix(it.text, "[x] ") || strings.HasPrefix(it.text, "[X] ") {
+			bullet, it.text = "☑", it.text[4:]
+		}
+
+		indent := strings.Repeat("  ", it.indent)
+		hanging := textWidth(indent) + textWidth(bullet) + 1
+		for j, line := range r.wrap(r.inline(it.text), max(width-hanging, 1)) {
+			if j == 0 {
+				out = append(out, indent+bulletStyle.Render(bullet)+" "+line)
+			} else {
+				out = append(out, strings.Repeat(" ", hanging)+line)
+			}
+		}
+	}
+	return out
+}
+
+func isTableSeparator(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "|") {
+		return false
+	}
+	return strings.Trim(trimmed, "|-: ") == "" && strings.Contains(trimmed, "-")
+}
+
+// tableCells splits a "| a | b |" row into its cells
+func tableCells(line string) []string {
+	trimmed := strings.TrimSpace(line)
+	trimmed = strings.TrimPrefix(trimmed, "|")
+	trimmed = strings.TrimSuffix(trimmed, "|")
+
+	cells := []string{}
+	var cell strings.Builder
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case trimmed[i] == '\\' && i+1 < len(trimmed) && trimmed[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case trimmed[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(trimmed[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func (r markdownRenderer) table(lines []string, width int) []string {
+	header := tableCells(lines[0])
+	cols := len(header)
+
+	aligns := make([]lipgloss.Position, cols)
+	for i, spec := range tableCells(lines[1]) {
+		if i >= cols {
+			break
+		}
+		switch left, right := strings.HasPrefix(spec, ":"), strings.HasSuffix(spec, ":"); {
+		case left && right:
+			aligns[i] = lipgloss.Center
+		case right:
+			aligns[i] = lipgloss.Right
+		default:
+			aligns[i] = lipgloss.Left
+		}
+	}
+
+	// Render every cell first so widths are measured on what's shown
+	rows := [][]string{}
+	for _, line := range append([]string{lines[0]}, lines[2:]...) {
+		cells := tableCells(line)
+		row := make([]string, cols)
+		for i := range row {
+			if i < len(cells) {
+				row[i] = r.inline(cells[i])
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, cols)
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], textWidth(cell))
+		}
+	}
+
+	// Borders take cols+1 cells, padding two per column; shrink the widest
+	// column until the rest fits
+	available := width - (cols + 1) - 2*cols
+	for sum(widths) > available {
+		widest := 0
+		for i := range widths {
+			if widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= 1 {
+			break
+		}
+		widths[widest]--
+	}
+
+	border := lipgloss.NewStyle().Foreground(r.theme.Color(theme.Muted))
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(r.theme.Color(theme.Primary))
+	rule := func(left, mid, right string) string {
+		parts := make([]string, cols)
+		for i, w := range widths {
+			parts[i] = strings.Repeat("─", w+2)
+		}
+		return border.Render(left + strings.Join(parts, mid) + right)
+	}
+
+	out := []string{rule("┌", "┬", "┐")}
+	for i, row := range rows {
+		parts := make([]string, cols)
+		for c, cell := range row {
+			cell = DefaultTruncation.Truncate(cell, widths[c])
+			if i == 0 {
+				cell = headerStyle.Render(ansi.Strip(cell))
+			}
+			parts[c] = " " + alignCell(cell, widths[c], aligns[c]) + " "
+		}
+		out = append(out, border.Render("│")+strings.Join(parts, border.Render("│"))+border.Render("│"))
+		if i == 0 {
+			out = append(out, rule("├", "┼", "┤"))
+		}
+	}
+	return append(out, rule("└", "┴", "┘"))
+}
+
+func alignCell(cell string, width int, align lipgloss.Position) string {
+	gap := max(width-textWidth(cell), 0)
+	switch align {
+	case lipgloss.Right:
+		return strings.Repeat(" ", gap) + cell
+	case lipgloss.Center:
+		return strings.Repeat(" ", gap/2) + cell + strings.Repeat(" ", gap-gap/2)
+	}
+	return cell + strings.Repeat(" ", gap)
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+// wrap breaks styled text into lines of at most width cells
+func (r markdownRenderer) wrap(text string, width int) []string {
+	return strings.Split(ansi.Wrap(text, width, ""), "\n")
+}
+
+// inline renders emphasis, code spans and links by way of TextLayout markup
+func (r markdownRenderer) inline(text string) string {
+	return renderMarkup(inlineMarkup(text), r.theme)
+}
+
+// inlineMarkup translates inline Markdown into markup tags
+func inlineMarkup(text string) string {
+	var b strings.Builder
+	bold, italic := false, false
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_[]()#+-.!|", rune(rest[1])):
+			b.WriteString(escapeMarkup(rest[1:2]))
+			i += 2
+
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				b.WriteString("[fg=primary]" + escapeMarkup(rest[1:end+1]) + "[/fg]")
+				i += end + 2
+				continue
+			}
+			b.WriteByte('`')
+			i++
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			delim := rest[:2]
+			if bold || strings.Contains(rest[2:], delim) {
+				if bold {
+					b.WriteString("[/b]")
+				} else {
+					b.WriteString("[b]")
+				}
+				bold = !bold
+			} else {
+				b.WriteString(delim)
+			}
+			i += 2
+
+		case rest[0] == '*' || rest[0] == '_' && (italic || i == 0 || !isWordByte(text[i-1])):
+			delim := rest[:1]
+			if italic || strings.Contains(rest[1:], delim) {
+				if italic {
+					b.WriteString("[/i]")
+				} else {
+					b.WriteString("[i]")
+				}
+				italic = !italic
+			} else {
+				b.WriteString(delim)
+			}
+			i++
+
+		case rest[0] == '[':
+			if label, url, n, ok := markdownLink(rest); ok {
+				b.WriteString("[link=" + url + "][u][fg=primary]" + inlineMarkup(label) + "[/fg][/u][/link]")
+				if accessibility.Markers {
+					b.WriteString(" (" + escapeMarkup(url) + ")")
+				}
+				i += n
+				continue
+			}
+			b.WriteString("[[")
+			i++
+
+		default:
+			b.WriteByte(rest[0])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// markdownLink parses "[label](url)" at the start of s
+func markdownLink(s string) (label, url string, n int, ok bool) {
+	close := strings.Index(s, "](")
+	if close < 0 {
+		return "", "", 0, false
+	}
+	end := strings.IndexByte(s[close+2:], ')')
+	if end < 0 {
+		return "", "", 0, false
+	}
+	url = s[close+2 : close+2+end]
+	if strings.ContainsAny(url, " ]") {
+		return "", "", 0, false
+	}
+	return s[1:close], url, close + 3 + end, true
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+// escapeMarkup makes s render literally as markup
+func escapeMarkup(s string) string {
+	return strings.ReplaceAll(s, "[", "[[")
+}
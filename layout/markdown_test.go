@@ -0,0 +1,50 @@
+package layout
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cactircool/bitwave/theme"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestMarkdownBlocks(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			"table after a paragraph",
+			"Some text\n| a | b |\n|---|---|\n| 1 | 2 |",
+			[]string{"Some text", "", "┌───┬───┐", "│ a │ b │", "├───┼───┤", "│ 1 │ 2 │", "└───┴───┘"},
+		},
+		{
+			"paragraph after a table",
+			"| a |\n|---|\n| 1 |\nafter",
+			[]string{"┌───┐", "│ a │", "├───┤", "│ 1 │", "└───┘", "", "after"},
+		},
+		{
+			"pipe without a separator stays text",
+			"Some text\n| not a table |",
+			[]string{"Some text | not a table |"},
+		},
+		{
+			"heading after a paragraph",
+			"Some text\n# Title",
+			[]string{"Some text", "", "Title"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Split(ansi.Strip(renderMarkdown(tt.src, 40, theme.Current())), "\n")
+			for i := range got {
+				got[i] = strings.TrimRight(got[i], " ")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package layout
+
+import (
+	"strings"
+
+	"github.com/cactircool/bitwave/theme"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Markup understood by TextLayout:
+//
+//	[b]bold[/b] [i]italic[/i] [u]underline[/u] [s]strikethrough[/s]
+//	[fg=muted]...[/fg] [bg=#303030]...[/bg]   a theme role, hex color or ANSI number
+//	[link=https://example.com]text[/link]      a terminal hyperlink
+//
+// Tags nest, "[/]" closes the innermost open tag and "[[" is a literal "["
+// Anything that isn't a valid tag is left as text
+
+// markupTag is an open tag while parsing
+type markupTag struct {
+	name  string
+	value string
+}
+
+// markupSpan is a run of text with one set of open tags
+type markupSpan struct {
+	text string
+	tags []markupTag
+}
+
+// parseMarkup splits s into spans, dropping the tags themselves
+func parseMarkup(s string, t *theme.Theme) []markupSpan {
+	spans := []markupSpan{}
+	open := []markupTag{}
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+		spans = append(spans, markupSpan{
+			text: text.String(),
+			tags: append([]markupTag(nil), open...),
+		})
+		text.Reset()
+	}
+
+	for len(s) > 0 {
+		if strings.HasPrefix(s, "[[") {
+			text.WriteByte('[')
+			s = s[2:]
+			continue
+		}
+		if s[0] != '[' {
+			next := strings.IndexByte(s, '[')
+			if next < 0 {
+				next = len(s)
+			}
+			text.WriteString(s[:next])
+			s = s[next:]
+			continue
+		}
+
+		end := strings.IndexByte(s, ']')
+		if end < 0 {
+			text.WriteString(s)
+			break
+		}
+		body := s[1:end]
+
+		if strings.HasPrefix(body, "/") {
+			if i := closingTag(open, body[1:]); i >= 0 {
+				flush()
+				open = append(open[:i], open[i+1:]...)
+				s = s[end+1:]
+				continue
+			}
+		} else if tag, ok := parseTag(body, t); ok {
+			flush()
+			open = append(open, tag)
+			s = s[end+1:]
+			continue
+		}
+
+		// Not a tag after all
+		text.WriteByte('[')
+		s = s[1:]
+	}
+	flush()
+
+	return spans
+}
+
+// closingTag returns the index of the innermost open tag that "[/name]"
+// closes, or -1
+func closingTag(open []markupTag, name string) int {
+	for i := len(open) - 1; i >= 0; i-- {
+		if name == "" || open[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func parseTag(body string, t *theme.Theme) (markupTag, bool) {
+	name, value, hasValue := strings.Cut(body, "=")
+	switch name {
+	case "b", "i", "u", "s":
+		return markupTag{name: name}, !hasValue
+	case "fg", "bg":
+		_, ok := markupColor(value, t)
+		return markupTag{name: name, value: value}, ok
+	case "link":
+		return markupTag{name: name, value: value}, value != ""
+	}
+	return markupTag{}, false
+}
+
+// markupColor resolves a theme role, hex color or ANSI color number
+func markupColor(value string, t *theme.Theme) (lipgloss.TerminalColor, bool) {
+	if value == "" {
+		return nil, false
+	}
+	if role := theme.Role(value); role.Valid() {
+		return t.Color(role), true
+	}
+	if strings.HasPrefix(value, "#") || strings.Trim(value, "0123456789") == "" {
+		return lipgloss.Color(value), true
+	}
+	return nil, false
+}
+
+// renderMarkup turns markup into styled text
+func renderMarkup(s string, t *theme.Theme) string {
+	var b strings.Builder
+	for _, span := range parseMarkup(s, t) {
+		style := lipgloss.NewStyle()
+		link := ""
+		for _, tag := range span.tags {
+			switch tag.name {
+			case "b":
+				style = style.Bold(true)
+			case "i":
+				style = style.Italic(true)
+			case "u":
+				style = style.Underline(true)
+			case "s":
+				style = style.Strikethrough(true)
+			case "fg":
+				color, _ := markupColor(tag.value, t)
+				style = style.Foreground(color)
+			case "bg":
+				color, _ := markupColor(tag.value, t)
+				style = style.Background(color)
+			case "link":
+				link = tag.value
+			}
+		}
+
+		if link != "" {
+			b.WriteString(ansi.SetHyperlink(link))
+		}
+		// Line by line, since lipgloss pads multi-line text to a block
+		lines := strings.Split(span.text, "\n")
+		for i, line := range lines {
+			if line != "" {
+				lines[i] = style.Render(line)
+			}
+		}
+		b.WriteString(strings.Join(lines, "\n"))
+		if link != "" {
+			b.WriteString(ansi.ResetHyperlink())
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,170 @@
+package layout
+
+import (
+	"sync"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ObservableChangedMsg is broadcast when an observable changes so bound
+// widgets can pick up the new state
+type ObservableChangedMsg struct{}
+
+// program is the running tea program, used to wake the UI when an
+// observable is mutated outside of Update
+var program atomic.Pointer[tea.Program]
+
+// AttachProgram lets observable mutations from any goroutine trigger a re-render
+func (r *RootLayout) AttachProgram(p *tea.Program) {
+	program.Store(p)
+}
+
+// changePending is set while an ObservableChangedMsg is on its way to the root,
+// so a burst of mutations wakes the UI once rather than once per mutation
+var changePending atomic.Bool
+
+func notifyChanged() {
+	if p := program.Load(); p != nil && changePending.CompareAndSwap(false, true) {
+		// Send from a goroutine since we may be inside Update already
+		go p.Send(ObservableChangedMsg{})
+	}
+}
+
+// Observable holds a single value that widgets can be bound to
+// It is safe to mutate from any goroutine
+type Observable[T any] struct {
+	mu      sync.RWMutex
+	value   T
+	version uint64
+}
+
+func NewObservable[T any](value T) *Observable[T] {
+	return &Observable[T]{value: value}
+}
+
+func (o *Observable[T]) Get() T {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.value
+}
+
+func (o *Observable[T]) Set(value T) {
+	o.mu.Lock()
+	o.value = value
+	o.version++
+	o.mu.Unlock()
+	notifyChanged()
+}
+
+// Update replaces the value with fn applied to the current value
+func (o *Observable[T]) Update(fn func(T) T) {
+	o.mu.Lock()
+	o.value = fn(o.value)
+	o.version++
+	o.mu.Unlock()
+	notifyChanged()
+}
+
+// Version increases every time the value changes
+func (o *Observable[T]) Version() uint64 {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.version
+}
+
+// ObservableList holds a collection that widgets can be bound to
+// It is safe to mutate from any goroutine
+type ObservableList[T any] struct {
+	mu      sync.RWMutex
+	items   []T
+	version uint64
+}
+
+func NewObservableList[T any](items ...T) *ObservableList[T] {
+	return &ObservableList[T]{items: append([]T(nil), items...)}
+}
+
+// Items returns a copy of the current items
+func (o *ObservableList[T]) Items() []T {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return append([]T(nil), o.items...)
+}
+
+func (o *ObservableList[T]) Len() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.items)
+}
+
+func (o *ObservableList[T]) At(index int) T {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.items[index]
+}
+
+func (o *ObservableList[T]) Append(items ...T) {
+	o.mutate(func(cur []T) []T {
+		return append(cur, items...)
+	})
+}
+
+func (o *ObservableList[T]) Insert(index int, item T) {
+	o.mutate(func(cur []T) []T {
+		if index < 0 || index > len(cur) {
+			return cur
+		}
+		cur = append(cur, item)
+		copy(cur[index+1:], cur[index:])
+		cur[index] = item
+		return cur
+	})
+}
+
+func (o *ObservableList[T]) SetAt(index int, item T) {
+	o.mutate(func(cur []T) []T {
+		if index >= 0 && index < len(cur) {
+			cur[index] = item
+		}
+		return cur
+	})
+}
+
+func (o *ObservableList[T]) RemoveAt(index int) {
+	o.mutate(func(cur []T) []T {
+		if index < 0 || index >= len(cur) {
+			return cur
+		}
+		return append(cur[:index], cur[index+1:]...)
+	})
+}
+
+// Replace swaps the whole collection for items
+func (o *ObservableList[T]) Replace(items []T) {
+	o.mutate(func([]T) []T {
+		return append([]T(nil), items...)
+	})
+}
+
+// Version increases every time the collection changes
+func (o *ObservableList[T]) Version() uint64 {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.version
+}
+
+func (o *ObservableList[T]) mutate(fn func([]T) []T) {
+	o.mu.Lock()
+	o.items = fn(o.items)
+	o.version++
+	o.mu.Unlock()
+	notifyChanged()
+}
+
+// snapshot returns the items and the version they belong to
+func (o *ObservableList[T]) snapshot() ([]T, uint64) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return append([]T(nil), o.items...), o.version
+}
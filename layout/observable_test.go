@@ -0,0 +1,64 @@
+package layout
+
+import (
+	"io"
+	"sync"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// changeCounter counts the ObservableChangedMsgs that reach the root and quits
+// after the first, behind any that were already queued
+type changeCounter struct {
+	*RootLayout
+	changes int
+}
+
+func (c *changeCounter) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	c.RootLayout.Update(msg)
+	if _, ok := msg.(ObservableChangedMsg); ok {
+		c.changes++
+		return c, tea.Quit
+	}
+	return c, nil
+}
+
+func TestObservableCoalescesChanges(t *testing.T) {
+	counter := &changeCounter{RootLayout: NewRootLayout(Vertical)}
+	p := tea.NewProgram(counter, tea.WithInput(nil), tea.WithOutput(io.Discard), tea.WithoutRenderer(), tea.WithoutSignalHandler())
+	counter.AttachProgram(p)
+	t.Cleanup(func() {
+		program.Store(nil)
+		changePending.Store(false)
+	})
+
+	// Flood before the program runs, so every send is still waiting for it
+	text := NewObservable("")
+	items := NewObservableList[string]()
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				text.Update(func(s string) string { return s + "." })
+				items.Append("item")
+			}
+		}()
+	}
+	wg.Wait()
+	if len(text.Get()) != 1000 || items.Len() != 1000 {
+		t.Fatalf("lost changes: %d text, %d items", len(text.Get()), items.Len())
+	}
+
+	if _, err := p.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if counter.changes != 1 {
+		t.Errorf("%d change messages for one burst, want 1", counter.changes)
+	}
+	if changePending.Load() {
+		t.Error("change still pending after the root handled it")
+	}
+}
@@ -0,0 +1,57 @@
+package layout
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// placeOverlay draws top over base with its top-left corner at x, y
+// Both may contain ANSI styling; cells of base outside top are kept
+func placeOverlay(base, top string, x, y int) string {
+	baseLines := strings.Split(base, "\n")
+	topLines := strings.Split(top, "\n")
+	topWidth := lipgloss.Width(top)
+
+	for i, line := range topLines {
+		row := y + i
+		if row < 0 {
+			continue
+		}
+		for row >= len(baseLines) {
+			baseLines = append(baseLines, "")
+		}
+
+		under := baseLines[row]
+		left := ansi.Truncate(under, x, "")
+		if pad := x - ansi.StringWidth(left); pad > 0 {
+			left += strings.Repeat(" ", pad)
+		}
+		right := ansi.TruncateLeft(under, x+topWidth, "")
+		if ansi.StringWidth(under) <= x+topWidth {
+			right = ""
+		}
+
+		if pad := topWidth - ansi.StringWidth(line); pad > 0 {
+			line += strings.Repeat(" ", pad)
+		}
+		baseLines[row] = left + line + right
+	}
+
+	return strings.Join(baseLines, "\n")
+}
+
+// placeBottomRight draws top over the bottom-right corner of base
+func placeBottomRight(base, top string) string {
+	x := lipgloss.Width(base) - lipgloss.Width(top) - 1
+	y := lipgloss.Height(base) - lipgloss.Height(top) - 1
+	return placeOverlay(base, top, max(x, 0), max(y, 0))
+}
+
+// placeCenter draws top over the middle of base
+func placeCenter(base, top string) string {
+	x := (lipgloss.Width(base) - lipgloss.Width(top)) / 2
+	y := (lipgloss.Height(base) - lipgloss.Height(top)) / 2
+	return placeOverlay(base, top, max(x, 0), max(y, 0))
+}
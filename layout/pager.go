@@ -0,0 +1,554 @@
+package layout
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cactircool/bitwave/bindings"
+	"github.com/cactircool/bitwave/theme"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// pagerPrompt is what the pager's prompt line is asking for
+type pagerPrompt int
+
+const (
+	promptNone pagerPrompt = iota
+	promptSearch
+	promptSearchBackward
+	promptLine
+)
+
+// pagerRow is one screen row of a document line, which takes several when wrapped
+type pagerRow struct {
+	line  int
+	first bool // First row of its line, where the line number goes
+	text  string
+}
+
+// pagerRowsKey is everything the rows depend on
+type pagerRowsKey struct {
+	width       int
+	wrap        bool
+	lineNumbers bool
+	query       string
+	version     int
+	theme       *theme.Theme
+}
+
+// PagerLayout shows a long document like less: scrolling by line, half page
+// and page, searching forward with / and backward with ?, and jumping to a
+// line with :
+// While focused it claims the search backward key even though ? is also the
+// global help key
+type PagerLayout struct {
+	Identity
+	DisabledState
+	title   string
+	lines   []string
+	version int // Bumped when lines change
+	width   int
+	height  int
+
+	top         int // Index of the first row shown
+	offsetX     int // Horizontal scroll without wrapping
+	wrap        bool
+	lineNumbers bool
+
+	query      string
+	ignoreCase bool  // Smart case: only when the query is all lower case
+	backward   bool  // Direction of the last search, which n repeats
+	matches    []int // Lines containing query, in order
+	message    string
+
+	prompt pagerPrompt
+	input  textinput.Model
+
+	rows    []pagerRow
+	rowsKey pagerRowsKey
+
+	keymap       *bindings.Keymap
+	promptKeymap *bindings.Keymap
+}
+
+func NewPagerLayout(text string) *PagerLayout {
+	p := &PagerLayout{
+		wrap:         true,
+		input:        textinput.New(),
+		keymap:       bindings.Pager.Clone(),
+		promptKeymap: bindings.PagerPrompt.Clone(),
+	}
+	p.SetText(text)
+	return p
+}
+
+// LoadFile replaces the document with the file at path, titled with its name
+func (p *PagerLayout) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	p.SetText(string(data))
+	p.title = filepath.Base(path)
+	return nil
+}
+
+// SetText replaces the document and goes back to the top
+func (p *PagerLayout) SetText(text string) {
+	lines := strings.Split(strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = sanitizeLine(line)
+	}
+	p.lines = lines
+	p.version++
+	p.top, p.offsetX = 0, 0
+	p.rows = nil
+	p.findMatches()
+}
+
+// SetTitle names the document in the status line
+func (p *PagerLayout) SetTitle(title string) {
+	p.title = title
+}
+
+// SetWrap turns soft wrapping on or off; it's on by default
+func (p *PagerLayout) SetWrap(wrap bool) {
+	p.wrap = wrap
+	p.offsetX = 0
+}
+
+// SetLineNumbers turns the line number gutter on or off
+func (p *PagerLayout) SetLineNumbers(show bool) {
+	p.lineNumbers = show
+}
+
+// Keymap returns the pager's keymap, which can be overridden per pager
+func (p *PagerLayout) Keymap() *bindings.Keymap {
+	return p.keymap
+}
+
+// PromptKeymap returns the keymap used while typing a search or line number
+func (p *PagerLayout) PromptKeymap() *bindings.Keymap {
+	return p.promptKeymap
+}
+
+// ActiveKeymap returns the prompt keymap while prompting, the pager keymap otherwise
+func (p *PagerLayout) ActiveKeymap() *bindings.Keymap {
+	if p.prompt != promptNone {
+		return p.promptKeymap
+	}
+	return p.keymap
+}
+
+func (p *PagerLayout) SetKeymaps(keymap, promptKeymap *bindings.Keymap) {
+	p.keymap = keymap
+	p.promptKeymap = promptKeymap
+}
+
+func (p *PagerLayout) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+	p.input.Width = max(width-2, 1)
+}
+
+func (p *PagerLayout) bodyHeight() int {
+	return max(p.height-1, 0)
+}
+
+// gutterWidth is the width of the line numbers and the space after them
+func (p *PagerLayout) gutterWidth() int {
+	if !p.lineNumbers {
+		return 0
+	}
+	return len(strconv.Itoa(len(p.lines))) + 1
+}
+
+// layoutRows returns the document broken into screen rows, rebuilding
+// them when the width, wrapping or search changed
+// The top row stays on the same line across rebuilds
+func (p *PagerLayout) layoutRows() []pagerRow {
+	key := pagerRowsKey{
+		width:       p.width,
+		wrap:        p.wrap,
+		lineNumbers: p.lineNumbers,
+		query:       p.query,
+		version:     p.version,
+		theme:       theme.Current(),
+	}
+	if p.rows != nil && key == p.rowsKey {
+		return p.rows
+	}
+
+	topLine := 0
+	if p.top < len(p.rows) {
+		topLine = p.rows[p.top].line
+	}
+
+	width := max(p.width-p.gutterWidth(), 1)
+	match := lipgloss.NewStyle().Reverse(true)
+	rows := make([]pagerRow, 0, len(p.lines))
+	for i, line := range p.lines {
+		if positions := p.matchPositions(line); len(positions) > 0 {
+			// Matched lines lose their own colors so the matches stand out
+			line = highlightMatches(ansi.Strip(line), positions, lipgloss.NewStyle(), match)
+		}
+		parts := []string{line}
+		if p.wrap {
+			parts = strings.Split(ansi.Hardwrap(line, width, true), "\n")
+		}
+		for j, part := range parts {
+			rows = append(rows, pagerRow{line: i, first: j == 0, text: part})
+		}
+	}
+
+	p.rows, p.rowsKey = rows, key
+	p.top = p.rowOf(topLine)
+	return rows
+}
+
+// rowOf returns the first row of line, keeping a full page on screen
+func (p *PagerLayout) rowOf(line int) int {
+	rows := p.rows
+	for i, row := range rows {
+		if row.line >= line {
+			return p.clampTop(i)
+		}
+	}
+	return p.clampTop(len(rows))
+}
+
+// clampTop keeps a full page on screen, and the top row inside the document
+// even when there's no room to show it
+func (p *PagerLayout) clampTop(top int) int {
+	return max(min(top, len(p.rows)-max(p.bodyHeight(), 1)), 0)
+}
+
+func (p *PagerLayout) scroll(delta int) {
+	p.layoutRows()
+	p.top = p.clampTop(p.top + delta)
+}
+
+// topLine returns the document line at the top of the screen
+func (p *PagerLayout) topLine() int {
+	rows := p.layoutRows()
+	if p.top < len(rows) {
+		return rows[p.top].line
+	}
+	return 0
+}
+
+// GoToLine scrolls line, counted from 1, to the top
+func (p *PagerLayout) GoToLine(line int) {
+	p.layoutRows()
+	p.top = p.rowOf(line - 1)
+}
+
+func (p *PagerLayout) matchPositions(line string) []int {
+	if p.query == "" {
+		return nil
+	}
+	return substringPositions(ansi.Strip(line), p.query, p.ignoreCase)
+}
+
+func (p *PagerLayout) findMatches() {
+	p.matches = p.matches[:0]
+	for i, line := range p.lines {
+		if len(p.matchPositions(line)) > 0 {
+			p.matches = append(p.matches, i)
+		}
+	}
+}
+
+// Search highlights every occurrence of query and jumps to the first line
+// containing it, searching down from the top of the screen, or up when
+// backward is set
+// The search ignores case unless query has upper case letters
+func (p *PagerLayout) Search(query string, backward bool) {
+	p.query = query
+	p.ignoreCase = query == strings.ToLower(query)
+	p.backward = backward
+	p.findMatches()
+	p.message = ""
+	if query == "" {
+		return
+	}
+
+	from := p.topLine()
+	if backward {
+		p.jump(from, -1, true)
+	} else {
+		p.jump(from, 1, true)
+	}
+}
+
+// jump scrolls to the next line with a match from line in direction step,
+// wrapping around the ends of the document
+func (p *PagerLayout) jump(line, step int, inclusive bool) {
+	if len(p.matches) == 0 {
+		p.message = "Pattern not found: " + p.query
+		return
+	}
+
+	target := -1
+	if step > 0 {
+		for _, m := range p.matches {
+			if m > line || inclusive && m == line {
+				target = m
+				break
+			}
+		}
+		if target < 0 {
+			target = p.matches[0]
+			p.message = "Search hit bottom, continuing at top"
+		}
+	} else {
+		for i := len(p.matches) - 1; i >= 0; i-- {
+			if m := p.matches[i]; m < line || inclusive && m == line {
+				target = m
+				break
+			}
+		}
+		if target < 0 {
+			target = p.matches[len(p.matches)-1]
+			p.message = "Search hit top, continuing at bottom"
+		}
+	}
+	p.GoToLine(target + 1)
+}
+
+func (p *PagerLayout) GetFocusState() FocusState {
+	if p.IsDisabled() {
+		return Disabled
+	}
+	return Focusable
+}
+
+func (p *PagerLayout) OnFocus(baseStyle lipgloss.Style) (lipgloss.Style, tea.Cmd) {
+	// Focus is drawn by the layout's FocusPolicy
+	return baseStyle, nil
+}
+
+func (p *PagerLayout) OnBlur() {
+	p.closePrompt()
+}
+
+// Describe summarizes the pager for the linear view
+func (p *PagerLayout) Describe() string {
+	description := "Pager"
+	if p.title != "" {
+		description += " " + p.title
+	}
+	first, last := p.visibleLines()
+	description += fmt.Sprintf(", lines %d-%d of %d", first, last, len(p.lines))
+	if p.query != "" {
+		description += fmt.Sprintf(", %d lines match %q", len(p.matches), p.query)
+	}
+	return description
+}
+
+// CapturesKey claims every key while prompting, and the search backward key
+// so it isn't taken for help
+func (p *PagerLayout) CapturesKey(key tea.KeyMsg) bool {
+	return p.prompt != promptNone || p.keymap.Matches(key, bindings.SearchBackward)
+}
+
+// IsActive reports whether a search or line number is being typed
+func (p *PagerLayout) IsActive() bool {
+	return p.prompt != promptNone
+}
+
+func (p *PagerLayout) openPrompt(prompt pagerPrompt) tea.Cmd {
+	p.prompt = prompt
+	p.message = ""
+	switch prompt {
+	case promptSearch:
+		p.input.Prompt = "/"
+	case promptSearchBackward:
+		p.input.Prompt = "?"
+	case promptLine:
+		p.input.Prompt = ":"
+	}
+	p.input.SetValue("")
+	return p.input.Focus()
+}
+
+func (p *PagerLayout) closePrompt() {
+	p.prompt = promptNone
+	p.input.Blur()
+}
+
+// runPrompt acts on what was typed at the prompt
+func (p *PagerLayout) runPrompt() {
+	value := strings.TrimSpace(p.input.Value())
+	prompt := p.prompt
+	p.closePrompt()
+
+	switch prompt {
+	case promptSearch, promptSearchBackward:
+		if value == "" {
+			// Like less, an empty search repeats the last one
+			value = p.query
+		}
+		p.Search(value, prompt == promptSearchBackward)
+	case promptLine:
+		line, err := strconv.Atoi(value)
+		if err != nil {
+			p.message = "Not a line number: " + value
+			return
+		}
+		p.GoToLine(line)
+	}
+}
+
+func (p *PagerLayout) Init() tea.Cmd {
+	return nil
+}
+
+func (p *PagerLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if set, ok := msg.(SetTextMsg); ok {
+		p.SetText(set.Text)
+		return p, nil
+	}
+	if !isKeyInput(msg) {
+		return p, nil
+	}
+
+	if p.prompt != promptNone {
+		action, _ := p.promptKeymap.ActionFor(msg)
+		switch action {
+		case bindings.Run:
+			p.runPrompt()
+			return p, nil
+		case bindings.Cancel:
+			p.closePrompt()
+			return p, nil
+		}
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		return p, cmd
+	}
+
+	p.message = ""
+	page := max(p.bodyHeight(), 1)
+	action, _ := p.keymap.ActionFor(msg)
+	switch action {
+	case bindings.Up:
+		p.scroll(-1)
+	case bindings.Down:
+		p.scroll(1)
+	case bindings.HalfPageUp:
+		p.scroll(-max(page/2, 1))
+	case bindings.HalfPageDown:
+		p.scroll(max(page/2, 1))
+	case bindings.PageUp:
+		p.scroll(-page)
+	case bindings.PageDown:
+		p.scroll(page)
+	case bindings.Top:
+		p.scroll(-len(p.layoutRows()))
+	case bindings.Bottom:
+		p.scroll(len(p.layoutRows()))
+	case bindings.Left:
+		if !p.wrap {
+			p.offsetX = max(p.offsetX-max(p.width/2, 1), 0)
+		}
+	case bindings.Right:
+		if !p.wrap {
+			p.offsetX += max(p.width/2, 1)
+		}
+	case bindings.Search:
+		return p, p.openPrompt(promptSearch)
+	case bindings.SearchBackward:
+		return p, p.openPrompt(promptSearchBackward)
+	case bindings.GoToLine:
+		return p, p.openPrompt(promptLine)
+	case bindings.NextMatch, bindings.PreviousMatch:
+		if p.query == "" {
+			break
+		}
+		step := 1
+		if p.backward != (action == bindings.PreviousMatch) {
+			step = -1
+		}
+		p.jump(p.topLine(), step, false)
+	case bindings.ToggleLineNumbers:
+		p.SetLineNumbers(!p.lineNumbers)
+	case bindings.ToggleWrap:
+		p.SetWrap(!p.wrap)
+	}
+	return p, nil
+}
+
+// visibleLines returns the first and last document lines on screen, counted from 1
+func (p *PagerLayout) visibleLines() (int, int) {
+	rows := p.layoutRows()
+	if len(rows) == 0 {
+		return 0, 0
+	}
+	end := min(p.top+p.bodyHeight(), len(rows)) - 1
+	return rows[p.top].line + 1, rows[max(end, p.top)].line + 1
+}
+
+func (p *PagerLayout) statusLine() string {
+	if p.prompt != promptNone {
+		return p.input.View()
+	}
+
+	t := theme.Current()
+	status := p.message
+	if status == "" {
+		first, last := p.visibleLines()
+		parts := []string{}
+		if p.title != "" {
+			parts = append(parts, p.title)
+		}
+		parts = append(parts, fmt.Sprintf("lines %d-%d/%d", first, last, len(p.lines)))
+		if p.top >= p.clampTop(len(p.rows)) {
+			parts = append(parts, "(END)")
+		} else {
+			parts = append(parts, fmt.Sprintf("%d%%", last*100/max(len(p.lines), 1)))
+		}
+		status = strings.Join(parts, " · ")
+	}
+	return lipgloss.NewStyle().Foreground(t.Color(theme.Muted)).Render(
+		DefaultTruncation.Truncate(status, p.width))
+}
+
+func (p *PagerLayout) View() string {
+	rows := p.layoutRows()
+	t := theme.Current()
+	gutter := lipgloss.NewStyle().Foreground(t.Color(theme.Muted))
+	gutterWidth := p.gutterWidth()
+	width := max(p.width-gutterWidth, 0)
+
+	lines := make([]string, 0, p.height)
+	end := min(p.top+p.bodyHeight(), len(rows))
+	for _, row := range rows[p.top:end] {
+		number := ""
+		if gutterWidth > 0 {
+			number = strings.Repeat(" ", gutterWidth)
+			if row.first {
+				number = gutter.Render(fmt.Sprintf("%*d ", gutterWidth-1, row.line+1))
+			}
+		}
+		text := row.text
+		if !p.wrap {
+			text = ansi.TruncateLeft(text, p.offsetX, "")
+		}
+		// Reset so an unterminated color doesn't bleed into the next row
+		lines = append(lines, number+ansi.Truncate(text, width, "")+ansi.ResetStyle)
+	}
+	for len(lines) < p.bodyHeight() {
+		lines = append(lines, gutter.Render("~"))
+	}
+	if p.height > 0 {
+		lines = append(lines, p.statusLine())
+	}
+	return lipgloss.NewStyle().Width(p.width).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,106 @@
+package layout
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func pagerText(lines int) string {
+	text := make([]string, lines)
+	for i := range text {
+		text[i] = fmt.Sprintf("line %d", i+1)
+	}
+	return strings.Join(text, "\n")
+}
+
+func TestPagerScroll(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   int
+		height  int
+		keys    []string
+		wantTop int // Document line at the top, counted from 1
+	}{
+		{"down", 100, 11, []string{"j", "j"}, 3},
+		{"up past the top", 100, 11, []string{"k", "k"}, 1},
+		{"page down", 100, 11, []string{"f"}, 11},
+		{"half page down", 100, 11, []string{"d"}, 6},
+		{"bottom keeps a full page", 100, 11, []string{"G"}, 91},
+		{"down past the bottom", 100, 11, []string{"G", "j", "j"}, 91},
+		{"shorter than the screen", 3, 11, []string{"G", "j"}, 1},
+		{"only a status line", 100, 1, []string{"G", "j"}, 100},
+		{"no room at all", 100, 0, []string{"G", "f"}, 100},
+		{"empty document", 0, 11, []string{"G", "k", "j"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPagerLayout(pagerText(tt.lines))
+			p.SetSize(40, tt.height)
+			press(p, tt.keys...)
+
+			if got := p.topLine() + 1; got != tt.wantTop {
+				t.Errorf("top line = %d, want %d", got, tt.wantTop)
+			}
+			p.View()
+		})
+	}
+}
+
+func TestPagerSearch(t *testing.T) {
+	text := "alpha\nbeta\nGamma\nalpha beta\ndelta\ngamma"
+	tests := []struct {
+		name    string
+		keys    []string
+		wantTop int
+		message string
+	}{
+		{"forward", []string{"/", "b", "e", "t", "a", "enter"}, 2, ""},
+		{"next wraps around", []string{"/", "b", "e", "t", "a", "enter", "n", "n"}, 2, "hit bottom"},
+		{"previous goes back", []string{"/", "b", "e", "t", "a", "enter", "n", "N"}, 2, ""},
+		{"backward", []string{"G", "?", "a", "l", "p", "h", "a", "enter"}, 4, ""},
+		{"backward wraps around", []string{"?", "d", "e", "l", "t", "a", "enter"}, 5, "hit top"},
+		{"smart case ignores case", []string{"/", "g", "a", "m", "enter"}, 3, ""},
+		{"smart case keeps upper case", []string{"j", "j", "j", "/", "G", "a", "m", "enter"}, 3, "hit bottom"},
+		{"no match", []string{"/", "z", "enter"}, 1, "Pattern not found"},
+		{"empty search repeats the last", []string{"/", "d", "enter", "k", "/", "enter"}, 5, ""},
+		{"go to line", []string{":", "4", "enter"}, 4, ""},
+		{"go to a line past the end", []string{":", "9", "9", "enter"}, 6, ""},
+		{"bad line number", []string{":", "x", "enter"}, 1, "Not a line number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPagerLayout(text)
+			p.SetSize(40, 2)
+			press(p, tt.keys...)
+
+			if got := p.topLine() + 1; got != tt.wantTop {
+				t.Errorf("top line = %d, want %d", got, tt.wantTop)
+			}
+			if !strings.Contains(p.message, tt.message) || tt.message == "" && p.message != "" {
+				t.Errorf("message = %q, want %q", p.message, tt.message)
+			}
+			p.View()
+		})
+	}
+}
+
+func TestPagerWrapKeepsPosition(t *testing.T) {
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = strings.Repeat("word ", 30)
+	}
+	p := NewPagerLayout(strings.Join(lines, "\n"))
+	p.SetSize(20, 5)
+	p.GoToLine(10)
+
+	press(p, "w")
+	if got := p.topLine() + 1; got != 10 {
+		t.Errorf("top line after unwrapping = %d, want 10", got)
+	}
+	press(p, "w", "#")
+	if got := p.topLine() + 1; got != 10 {
+		t.Errorf("top line after wrapping with line numbers = %d, want 10", got)
+	}
+	p.View()
+}
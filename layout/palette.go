@@ -0,0 +1,216 @@
+package layout
+
+import (
+	"strings"
+
+	"github.com/cactircool/bitwave/bindings"
+	"github.com/cactircool/bitwave/theme"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paletteRows is how many commands the palette shows at once
+const paletteRows = 10
+
+// Command is an entry in the command palette
+type Command struct {
+	Title string
+	Keys  []string // Shown next to the title
+	Run   func() tea.Cmd
+}
+
+// CommandProvider is implemented by models that add their own commands to
+// the palette while they're on the focus path
+// Every action in a KeymapProvider's active keymap is listed automatically
+type CommandProvider interface {
+	Commands() []Command
+}
+
+// commandPalette is the fuzzy-filtered command list opened from the root
+type commandPalette struct {
+	open     bool
+	input    textinput.Model
+	commands []Command
+	results  []fuzzyResult
+	cursor   int
+	offset   int
+}
+
+func newCommandPalette() commandPalette {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "Type a command..."
+	return commandPalette{input: input}
+}
+
+// AddCommand registers an app-wide command with the palette
+func (r *RootLayout) AddCommand(command Command) {
+	r.commands = append(r.commands, command)
+}
+
+// collectCommands returns the app's commands followed by those of every
+// enabled component along the focus path
+// Keymap actions are dispatched like keys, so they follow focus to the
+// component and are dropped if it has been disabled since
+func (r *RootLayout) collectCommands() []Command {
+	commands := append([]Command(nil), r.commands...)
+	commands = append(commands, r.themeCommands()...)
+	commands = append(commands, keymapCommands("Global", bindings.Global, r.dispatch)...)
+
+	for _, model := range focusPath() {
+		if isDisabled(model) {
+			continue
+		}
+		if provider, ok := model.(CommandProvider); ok {
+			commands = append(commands, provider.Commands()...)
+		}
+		if provider, ok := model.(KeymapProvider); ok {
+			commands = append(commands, keymapCommands(keymapTitle(provider.ActiveKeymap()), provider.ActiveKeymap(), r.dispatch)...)
+		}
+	}
+	return commands
+}
+
+// keymapCommands turns every action in keymap into a command that sends
+// the action through deliver
+func keymapCommands(title string, keymap *bindings.Keymap, deliver func(tea.Msg) tea.Cmd) []Command {
+	commands := []Command{}
+	for _, action := range helpActions(keymap) {
+		// Opening the palette from the palette makes no sense
+		if keymap == bindings.Global && action == bindings.Palette {
+			continue
+		}
+		binding, _ := keymap.Binding(action)
+		msg := bindings.ActionMsg{Action: action}
+		commands = append(commands, Command{
+			Title: title + ": " + binding.Help,
+			Keys:  binding.Keys,
+			Run: func() tea.Cmd {
+				return deliver(msg)
+			},
+		})
+	}
+	return commands
+}
+
+func (r *RootLayout) openPalette() tea.Cmd {
+	r.palette.open = true
+	r.palette.commands = r.collectCommands()
+	r.palette.input.SetValue("")
+	r.palette.filter()
+	return r.palette.input.Focus()
+}
+
+func (p *commandPalette) close() {
+	p.open = false
+	p.input.Blur()
+	p.commands = nil
+	p.results = nil
+}
+
+// filter re-runs the fuzzy match against the current query
+func (p *commandPalette) filter() {
+	titles := make([]string, len(p.commands))
+	for i, command := range p.commands {
+		titles[i] = command.Title
+	}
+	p.results = fuzzyFilter(p.input.Value(), titles)
+	p.cursor = 0
+	p.offset = 0
+}
+
+func (p *commandPalette) move(delta int) {
+	if len(p.results) == 0 {
+		return
+	}
+	p.cursor = (p.cursor + delta + len(p.results)) % len(p.results)
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+paletteRows {
+		p.offset = p.cursor - paletteRows + 1
+	}
+}
+
+// updatePalette handles a message while the palette is open
+func (r *RootLayout) updatePalette(msg tea.Msg) tea.Cmd {
+	p := &r.palette
+	if isKeyInput(msg) {
+		action, _ := bindings.CommandPalette.ActionFor(msg)
+		switch action {
+		case bindings.Up:
+			p.move(-1)
+			return nil
+		case bindings.Down:
+			p.move(1)
+			return nil
+		case bindings.Cancel:
+			p.close()
+			return nil
+		case bindings.Run:
+			if len(p.results) == 0 {
+				return nil
+			}
+			command := p.commands[p.results[p.cursor].index]
+			p.close()
+			if command.Run == nil {
+				return nil
+			}
+			return command.Run()
+		}
+	}
+
+	query := p.input.Value()
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	if p.input.Value() != query {
+		p.filter()
+	}
+	return cmd
+}
+
+func (p *commandPalette) View(width int) string {
+	boxWidth := min(max(width*2/3, 30), width)
+	innerWidth := boxWidth - 4 // Border and padding
+
+	t := theme.Current()
+	matchStyle := lipgloss.NewStyle().Bold(true).Underline(true)
+	keyStyle := lipgloss.NewStyle().Foreground(t.Color(theme.Muted))
+	cursorStyle := lipgloss.NewStyle().Background(t.Color(theme.Selection)).Foreground(t.Color(theme.Inverse))
+
+	lines := []string{p.input.View(), ""}
+	end := min(p.offset+paletteRows, len(p.results))
+	for i := p.offset; i < end; i++ {
+		result := p.results[i]
+		command := p.commands[result.index]
+
+		base := lipgloss.NewStyle()
+		if i == p.cursor {
+			base = cursorStyle
+		}
+
+		keys := make([]string, len(command.Keys))
+		for k, key := range command.Keys {
+			keys[k] = bindings.DisplayKey(key)
+		}
+		keyText := strings.Join(keys, "/")
+
+		title := highlightMatches(command.Title, result.positions, base, matchStyle)
+		gap := innerWidth - lipgloss.Width(title) - lipgloss.Width(keyText)
+		if gap < 1 {
+			gap = 1
+		}
+		lines = append(lines, title+base.Render(strings.Repeat(" ", gap))+keyStyle.Inherit(base).Render(keyText))
+	}
+	if len(p.results) == 0 {
+		lines = append(lines, keyStyle.Render("No matching commands"))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Color(theme.Primary)).
+		Padding(0, 1).
+		Width(boxWidth - 2).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,53 @@
+package layout
+
+import (
+	"reflect"
+	"testing"
+)
+
+// findCommand returns the command titled title, if there is one
+func findCommand(commands []Command, title string) (Command, bool) {
+	for _, command := range commands {
+		if command.Title == title {
+			return command, true
+		}
+	}
+	return Command{}, false
+}
+
+func TestPaletteKeymapCommands(t *testing.T) {
+	const toggle = "List: toggle selection"
+	tests := []struct {
+		name          string
+		disableBefore bool // Disabled before the palette collects commands
+		disableAfter  bool // Disabled between collecting and running
+		listed        bool
+		selected      []string
+	}{
+		{"runs on the focused list", false, false, true, []string{"a"}},
+		{"disabled lists have no commands", true, false, false, []string{}},
+		{"disabled after opening", false, true, true, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewListLayout("", 0)
+			l.AddItems([]string{"a", "b"})
+			root := newRoot(l)
+
+			l.SetDisabled(tt.disableBefore)
+			command, ok := findCommand(root.collectCommands(), toggle)
+			if ok != tt.listed {
+				t.Fatalf("%q listed = %v, want %v", toggle, ok, tt.listed)
+			}
+			if !ok {
+				return
+			}
+
+			l.SetDisabled(tt.disableAfter)
+			command.Run()
+			if got := selectedValues(l); !reflect.DeepEqual(got, tt.selected) {
+				t.Errorf("selected = %q, want %q", got, tt.selected)
+			}
+		})
+	}
+}
@@ -32,6 +32,27 @@ func (f *FocusStack) IsCurrent(layout *GenericLayout) bool {
 	return f.Current() == layout
 }
 
+// Contains reports whether layout is anywhere on the stack
+func (f *FocusStack) Contains(layout *GenericLayout) bool {
+	for _, l := range f.stack {
+		if l == layout {
+			return true
+		}
+	}
+	return false
+}
+
+// Drop removes layout and everything pushed after it
+// The root is never dropped
+func (f *FocusStack) Drop(layout *GenericLayout) {
+	for i := 1; i < len(f.stack); i++ {
+		if f.stack[i] == layout {
+			f.stack = f.stack[:i]
+			return
+		}
+	}
+}
+
 func (f *FocusStack) Depth() int {
 	return len(f.stack)
 }
@@ -45,6 +66,10 @@ func popFocus() *GenericLayout {
 	return globalFocusStack.Pop()
 }
 
+func dropFocus(layout *GenericLayout) {
+	globalFocusStack.Drop(layout)
+}
+
 func currentFocus() *GenericLayout {
 	return globalFocusStack.Current()
 }
@@ -52,3 +77,18 @@ func currentFocus() *GenericLayout {
 func isFocusCurrent(layout *GenericLayout) bool {
 	return globalFocusStack.IsCurrent(layout)
 }
+
+// focusPath returns the layouts on the focus stack, outermost first,
+// followed by the focused child of the innermost one
+func focusPath() []SizedModel {
+	path := make([]SizedModel, 0, len(globalFocusStack.stack)+1)
+	for _, layout := range globalFocusStack.stack {
+		path = append(path, layout)
+	}
+	if current := currentFocus(); current != nil {
+		if current.focused >= 0 && current.focused < len(current.children) {
+			path = append(path, current.children[current.focused].model)
+		}
+	}
+	return path
+}
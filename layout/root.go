@@ -1,19 +1,35 @@
 package layout
 
 import (
+	"github.com/cactircool/bitwave/bindings"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 type RootLayout struct {
-	inner *GenericLayout
+	inner    *GenericLayout
+	bus      *EventBus
+	sequence sequenceMatcher
+	showHelp bool
+	palette  commandPalette
+	commands []Command // App-wide palette commands
+
+	lastFocus  SizedModel // Focused component as of the last update
+	focusMoved bool       // Whether the last update moved focus
+
+	styledKey string // styleKey of the tree as of the last restyle
 }
 
 func NewRootLayout(direction Direction) *RootLayout {
 	layout := NewLayout(direction)
 	// Initialize the focus stack with the root layout
 	pushFocus(layout)
-	return &RootLayout{inner: layout}
+	return &RootLayout{
+		inner:    layout,
+		bus:      NewEventBus(),
+		sequence: sequenceMatcher{timeout: DefaultSequenceTimeout},
+		palette:  newCommandPalette(),
+	}
 }
 
 func (r *RootLayout) Add(model SizedModel, weight float64, style lipgloss.Style, gap int) {
@@ -24,8 +40,19 @@ func (r *RootLayout) AddStatic(model SizedModel, size int, style lipgloss.Style,
 	r.inner.AddStatic(model, size, style, gap)
 }
 
+// Remove detaches model from the root layout
+func (r *RootLayout) Remove(model SizedModel) tea.Cmd {
+	return r.inner.Remove(model)
+}
+
+// Bus returns the event bus components publish to
+func (r *RootLayout) Bus() *EventBus {
+	return r.bus
+}
+
 func (r *RootLayout) SetSize(width, height int) {
 	r.inner.SetSize(width, height)
+	r.restyle()
 }
 
 func (r *RootLayout) Init() tea.Cmd {
@@ -37,20 +64,106 @@ func (r *RootLayout) Init() tea.Cmd {
 	if focusCmd := r.inner.focusFirst(); focusCmd != nil {
 		cmds = append(cmds, focusCmd)
 	}
+	r.restyle()
 
 	return tea.Batch(cmds...)
 }
 
+// Find returns the component with the given ID anywhere in the tree, or nil
+func (r *RootLayout) Find(id string) SizedModel {
+	return r.inner.Find(id)
+}
+
 func (r *RootLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if v, ok := msg.(tea.WindowSizeMsg); ok {
+	cmd := r.update(msg)
+	// Focus, editing and disabled states may have changed
+	cmd = tea.Batch(cmd, r.inner.releaseDisabled())
+	r.restyleIfChanged()
+	r.trackFocus()
+	return r, cmd
+}
+
+func (r *RootLayout) update(msg tea.Msg) tea.Cmd {
+	// The open palette takes all input, and still needs its cursor to blink
+	var paletteCmd tea.Cmd
+	if r.palette.open {
+		if followsFocus(msg) {
+			return r.updatePalette(msg)
+		}
+		paletteCmd = r.updatePalette(msg)
+	}
+
+	switch v := msg.(type) {
+	case tea.WindowSizeMsg:
 		r.SetSize(v.Width, v.Height)
+	case eventMsg:
+		return r.bus.dispatch(v.event)
+	case ComponentRemovedMsg:
+		r.bus.unsubscribeOwner(v.Model)
+	case sequenceTimeoutMsg:
+		return r.sequenceTimedOut(v)
+	case ObservableChangedMsg:
+		// Cleared before the broadcast, so changes made while handling it wake the UI again
+		changePending.Store(false)
+	case tea.KeyMsg:
+		if cmd, handled := r.feedSequence(v); handled {
+			return cmd
+		}
+	}
+
+	return tea.Batch(paletteCmd, r.dispatch(msg))
+}
+
+// dispatch hands msg to the layout tree, after the root's own keys
+func (r *RootLayout) dispatch(msg tea.Msg) tea.Cmd {
+	if cmd, handled := r.handleRootKeys(msg); handled {
+		return cmd
 	}
 
 	model, cmd := r.inner.Update(msg)
 	r.inner = model.(*GenericLayout)
-	return r, cmd
+	return cmd
+}
+
+// handleRootKeys handles keys that belong to the root rather than a layout
+func (r *RootLayout) handleRootKeys(msg tea.Msg) (tea.Cmd, bool) {
+	if !isKeyInput(msg) {
+		return nil, false
+	}
+	if key, ok := msg.(tea.KeyMsg); ok && r.inner.leafCaptures(key) {
+		return nil, false
+	}
+
+	switch {
+	case bindings.Global.Matches(msg, bindings.Palette):
+		r.showHelp = false
+		return r.openPalette(), true
+	case bindings.Global.Matches(msg, bindings.Help):
+		r.showHelp = !r.showHelp
+		return nil, true
+	case r.showHelp && bindings.Global.Matches(msg, bindings.CycleEscape):
+		r.showHelp = false
+		return nil, true
+	}
+	return nil, false
 }
 
 func (r *RootLayout) View() string {
-	return r.inner.View()
+	if accessibility.Linear {
+		return r.linearView()
+	}
+
+	view := r.inner.View()
+	if r.showHelp {
+		view = placeCenter(view, helpView())
+	}
+	if r.palette.open {
+		width := lipgloss.Width(view)
+		box := r.palette.View(width)
+		view = placeOverlay(view, box, max((width-lipgloss.Width(box))/2, 0), 2)
+	}
+	if len(r.sequence.pending) > 0 {
+		view = placeBottomRight(view, r.whichKeyView())
+	}
+	return view
 }
@@ -0,0 +1,142 @@
+package layout
+
+import (
+	"github.com/cactircool/bitwave/bindings"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Message routing:
+//   - TargetedMsg goes straight to the component with the matching ID
+//   - Disabled components get everything except user input
+//   - BroadcastMsg, and any message that isn't user input, fans out to the whole tree
+//   - Key and mouse messages follow focus
+
+// TargetedMsg delivers Msg to the component whose ID matches Target,
+// wherever it sits in the tree and whether or not it has focus
+type TargetedMsg struct {
+	Target string
+	Msg    tea.Msg
+}
+
+// BroadcastMsg delivers Msg to every component in the tree,
+// even if Msg is a key message that would normally follow focus
+type BroadcastMsg struct {
+	Msg tea.Msg
+}
+
+// ComponentRemovedMsg is broadcast after a component is removed from a layout
+type ComponentRemovedMsg struct {
+	Model SizedModel
+}
+
+// SendTo returns a command that delivers msg to the component with the given ID
+func SendTo(id string, msg tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return TargetedMsg{Target: id, Msg: msg}
+	}
+}
+
+// Broadcast returns a command that delivers msg to every component in the tree
+func Broadcast(msg tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return BroadcastMsg{Msg: msg}
+	}
+}
+
+// followsFocus reports whether msg is user input that only the focus path should see
+func followsFocus(msg tea.Msg) bool {
+	switch msg.(type) {
+	case tea.KeyMsg, bindings.SequenceMsg, bindings.ActionMsg, tea.MouseMsg:
+		return true
+	}
+	return false
+}
+
+// isKeyInput reports whether msg is a key press, a completed key sequence
+// or an action triggered without keys
+func isKeyInput(msg tea.Msg) bool {
+	switch msg.(type) {
+	case tea.KeyMsg, bindings.SequenceMsg, bindings.ActionMsg:
+		return true
+	}
+	return false
+}
+
+// updateChild forwards msg to the child at index and stores the updated model
+// Disabled children ignore user input
+func (l *GenericLayout) updateChild(index int, msg tea.Msg) tea.Cmd {
+	if followsFocus(msg) && isDisabled(l.children[index].model) {
+		return nil
+	}
+	model, cmd := l.children[index].model.Update(msg)
+	l.children[index].model = model.(SizedModel)
+	return cmd
+}
+
+// broadcast forwards msg to every child
+// Nested layouts receive the original message so they keep fanning it out,
+// leaves receive the unwrapped payload
+func (l *GenericLayout) broadcast(msg tea.Msg) tea.Cmd {
+	inner := msg
+	if b, ok := msg.(BroadcastMsg); ok {
+		inner = b.Msg
+	}
+
+	cmds := make([]tea.Cmd, 0, len(l.children))
+	for i := range l.children {
+		if _, ok := l.children[i].model.(*GenericLayout); ok {
+			cmds = append(cmds, l.updateChild(i, msg))
+		} else {
+			cmds = append(cmds, l.updateChild(i, inner))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// deliver finds the target of msg in this subtree and forwards the payload to it
+// Returns false if no component with the target ID exists below this layout
+func (l *GenericLayout) deliver(msg TargetedMsg) (tea.Cmd, bool) {
+	for i := range l.children {
+		child := l.children[i].model
+		if id, ok := child.(Identifiable); ok && id.ID() == msg.Target {
+			if d, ok := msg.Msg.(DisabledMsg); ok {
+				if disableable, ok := child.(Disableable); ok {
+					disableable.SetDisabled(d.Disabled)
+				}
+				return nil, true
+			}
+			return l.updateChild(i, msg.Msg), true
+		}
+		if childLayout, ok := child.(*GenericLayout); ok {
+			if cmd, found := childLayout.deliver(msg); found {
+				return cmd, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Find returns the component with the given ID in this subtree, or nil
+func (l *GenericLayout) Find(id string) SizedModel {
+	for _, child := range l.children {
+		if ident, ok := child.model.(Identifiable); ok && ident.ID() == id {
+			return child.model
+		}
+		if childLayout, ok := child.model.(*GenericLayout); ok {
+			if found := childLayout.Find(id); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// walk calls fn for model and every component below it
+func walk(model SizedModel, fn func(SizedModel)) {
+	fn(model)
+	if l, ok := model.(*GenericLayout); ok {
+		for _, child := range l.children {
+			walk(child.model, fn)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package layout
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cactircool/bitwave/bindings"
+	"github.com/cactircool/bitwave/theme"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DefaultSequenceTimeout is how long the root waits for the next key of a sequence
+const DefaultSequenceTimeout = time.Second
+
+// sequenceMatcher buffers keys while they could still complete a multi-key sequence
+type sequenceMatcher struct {
+	pending []tea.KeyMsg
+	timeout time.Duration
+	tag     int // Bumped on every key so stale timeouts are ignored
+}
+
+type sequenceTimeoutMsg struct {
+	tag int
+}
+
+func (m *sequenceMatcher) steps() []string {
+	steps := make([]string, len(m.pending))
+	for i, key := range m.pending {
+		steps[i] = key.String()
+	}
+	return steps
+}
+
+func (m *sequenceMatcher) reset() {
+	m.pending = nil
+	m.tag++
+}
+
+// SetSequenceTimeout sets how long a partially typed sequence waits for its next key
+func (r *RootLayout) SetSequenceTimeout(timeout time.Duration) {
+	r.sequence.timeout = timeout
+}
+
+// keymapsOnPath returns the global keymap and the keymaps of every component
+// along the focus path
+func keymapsOnPath() []*bindings.Keymap {
+	keymaps := []*bindings.Keymap{bindings.Global}
+	for _, model := range focusPath() {
+		if provider, ok := model.(KeymapProvider); ok {
+			keymaps = append(keymaps, provider.ActiveKeymap())
+		}
+	}
+	return keymaps
+}
+
+// sequencesStartingWith returns every bound sequence that begins with pressed
+func sequencesStartingWith(pressed []string) []bindings.Sequence {
+	matches := []bindings.Sequence{}
+	for _, keymap := range keymapsOnPath() {
+		for _, seq := range keymap.Sequences() {
+			if seq.HasPrefix(pressed) {
+				matches = append(matches, seq)
+			}
+		}
+	}
+	return matches
+}
+
+// feedSequence runs key through the sequence matcher
+// Returns false if the key isn't part of a sequence and should be handled normally
+func (r *RootLayout) feedSequence(key tea.KeyMsg) (tea.Cmd, bool) {
+	if len(r.sequence.pending) == 0 && r.inner.leafCaptures(key) {
+		return nil, false
+	}
+
+	pressed := append(r.sequence.steps(), key.String())
+	exact, longer := false, false
+	for _, seq := range sequencesStartingWith(pressed) {
+		if len(seq.Steps) == len(pressed) {
+			exact = true
+		} else {
+			longer = true
+		}
+	}
+
+	switch {
+	case longer:
+		// Wait for more, and fire whatever we have if nothing comes
+		r.sequence.pending = append(r.sequence.pending, key)
+		r.sequence.tag++
+		tag := r.sequence.tag
+		return tea.Tick(r.sequence.timeout, func(time.Time) tea.Msg {
+			return sequenceTimeoutMsg{tag: tag}
+		}), true
+
+	case exact:
+		r.sequence.reset()
+		return r.dispatch(bindings.SequenceMsg{Keys: pressed}), true
+
+	case len(r.sequence.pending) > 0:
+		// Dead end - the buffered keys were ordinary key presses after all
+		cmd := r.flushSequence()
+		if next, handled := r.feedSequence(key); handled {
+			return tea.Batch(cmd, next), true
+		}
+		return tea.Batch(cmd, r.dispatch(key)), true
+	}
+
+	return nil, false
+}
+
+// sequenceTimedOut completes or abandons the pending sequence
+func (r *RootLayout) sequenceTimedOut(msg sequenceTimeoutMsg) tea.Cmd {
+	if msg.tag != r.sequence.tag || len(r.sequence.pending) == 0 {
+		return nil
+	}
+
+	pressed := r.sequence.steps()
+	for _, seq := range sequencesStartingWith(pressed) {
+		if len(seq.Steps) == len(pressed) {
+			r.sequence.reset()
+			return r.dispatch(bindings.SequenceMsg{Keys: pressed})
+		}
+	}
+	return r.flushSequence()
+}
+
+// flushSequence replays buffered keys as ordinary key presses
+func (r *RootLayout) flushSequence() tea.Cmd {
+	keys := r.sequence.pending
+	r.sequence.reset()
+
+	cmds := make([]tea.Cmd, 0, len(keys))
+	for _, key := range keys {
+		cmds = append(cmds, r.dispatch(key))
+	}
+	return tea.Batch(cmds...)
+}
+
+// leafCaptures reports whether the focused component along the focus path wants key
+func (l *GenericLayout) leafCaptures(key tea.KeyMsg) bool {
+	if current := currentFocus(); current != nil {
+		return current.focusedCaptures(key)
+	}
+	return l.focusedCaptures(key)
+}
+
+// whichKeyView renders the pending sequence and the keys that can follow it
+func (r *RootLayout) whichKeyView() string {
+	pressed := r.sequence.steps()
+
+	// One line per distinct next key
+	next := map[string]string{}
+	for _, seq := range sequencesStartingWith(pressed) {
+		if len(seq.Steps) == len(pressed) {
+			continue
+		}
+		step := seq.Steps[len(pressed)]
+		if len(seq.Steps) == len(pressed)+1 {
+			next[step] = seq.Help
+		} else if _, ok := next[step]; !ok {
+			next[step] = "+more"
+		}
+	}
+
+	keys := make([]string, 0, len(next))
+	for key := range next {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	t := theme.Current()
+	keyStyle := lipgloss.NewStyle().Bold(true).Foreground(t.Color(theme.Primary))
+	lines := make([]string, 0, len(keys)+1)
+	lines = append(lines, keyStyle.Render(displaySteps(pressed)+" …"))
+	for _, key := range keys {
+		lines = append(lines, keyStyle.Render(bindings.DisplayKey(key))+"  "+next[key])
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Color(theme.Primary)).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+func displaySteps(steps []string) string {
+	shown := make([]string, len(steps))
+	for i, step := range steps {
+		shown[i] = bindings.DisplayKey(step)
+	}
+	return strings.Join(shown, " ")
+}
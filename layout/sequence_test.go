@@ -0,0 +1,53 @@
+package layout
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newRoot builds a sized root holding models, with focus on the first
+func newRoot(models ...SizedModel) *RootLayout {
+	globalFocusStack = &FocusStack{}
+	root := NewRootLayout(Vertical)
+	for _, model := range models {
+		root.Add(model, 1, lipgloss.NewStyle(), 0)
+	}
+	root.Init()
+	root.SetSize(40, 20)
+	return root
+}
+
+func TestLeaderSequences(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []string
+		pending  int // Keys still held by the sequence matcher
+		selected []string
+		help     bool
+	}{
+		{"space reaches the list at once", []string{"space"}, 0, []string{"a"}, false},
+		{"leader waits for the next key", []string{"\\"}, 1, []string{}, false},
+		{"leader h opens help", []string{"\\", "h"}, 0, []string{}, true},
+		{"g g still waits", []string{"g"}, 1, []string{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewListLayout("", 0)
+			l.AddItems([]string{"a", "b"})
+			root := newRoot(l)
+			press(root, tt.keys...)
+
+			if got := len(root.sequence.pending); got != tt.pending {
+				t.Errorf("%d keys pending, want %d", got, tt.pending)
+			}
+			if got := selectedValues(l); !reflect.DeepEqual(got, tt.selected) {
+				t.Errorf("selected = %q, want %q", got, tt.selected)
+			}
+			if root.showHelp != tt.help {
+				t.Errorf("help shown = %v, want %v", root.showHelp, tt.help)
+			}
+		})
+	}
+}
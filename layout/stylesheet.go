@@ -0,0 +1,431 @@
+package layout
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Stylesheet styles layout children by selector instead of per Add call
+//
+// A selector is one or more compounds separated by spaces, each matching a
+// component nested inside the one before it, e.g. "Layout.sidebar List:focused"
+// A compound is an optional type ("List", "ListLayout", "Layout" or "*")
+// followed by any number of "#id", ".class" and ":state" parts
+// States are focused, active and disabled
+//
+// Matching rules are applied from least to most specific, and the style
+// passed to Add goes on top of them all
+// Foreground and text attributes are inherited from the enclosing layout
+type Stylesheet struct {
+	rules []styleRule
+}
+
+type styleRule struct {
+	selector    []compound
+	style       lipgloss.Style
+	specificity int
+}
+
+// compound is one space-separated part of a selector
+type compound struct {
+	typeName string // Empty matches any type
+	id       string
+	classes  []string
+	states   []string
+}
+
+// Component states a selector can target
+const (
+	stateFocused  = "focused"
+	stateActive   = "active"
+	stateDisabled = "disabled"
+)
+
+func NewStylesheet() *Stylesheet {
+	return &Stylesheet{}
+}
+
+// Add appends a rule styling everything selector matches
+func (s *Stylesheet) Add(selector string, style lipgloss.Style) error {
+	compounds, err := parseSelector(selector)
+	if err != nil {
+		return err
+	}
+	s.rules = append(s.rules, styleRule{
+		selector:    compounds,
+		style:       style,
+		specificity: specificity(compounds),
+	})
+	return nil
+}
+
+// MustAdd is Add for selectors known to be valid, like literals in code
+// It panics if selector doesn't parse
+func (s *Stylesheet) MustAdd(selector string, style lipgloss.Style) {
+	if err := s.Add(selector, style); err != nil {
+		panic(err)
+	}
+}
+
+func parseSelector(selector string) ([]compound, error) {
+	fields := strings.Fields(selector)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty selector")
+	}
+
+	compounds := make([]compound, 0, len(fields))
+	for _, field := range fields {
+		c, err := parseCompound(field)
+		if err != nil {
+			return nil, fmt.Errorf("selector %q: %w", selector, err)
+		}
+		compounds = append(compounds, c)
+	}
+	return compounds, nil
+}
+
+func parseCompound(field string) (compound, error) {
+	var c compound
+
+	// Split into the type and "#id", ".class", ":state" parts
+	start := strings.IndexAny(field, "#.:")
+	if start < 0 {
+		start = len(field)
+	}
+	if typeName := field[:start]; typeName != "*" {
+		c.typeName = typeName
+	}
+
+	rest := field[start:]
+	for rest != "" {
+		prefix := rest[0]
+		end := strings.IndexAny(rest[1:], "#.:")
+		if end < 0 {
+			end = len(rest) - 1
+		}
+		name := rest[1 : end+1]
+		rest = rest[end+1:]
+
+		if name == "" {
+			return c, fmt.Errorf("empty name after %q", prefix)
+		}
+		switch prefix {
+		case '#':
+			if c.id != "" {
+				return c, fmt.Errorf("more than one id")
+			}
+			c.id = name
+		case '.':
+			c.classes = append(c.classes, name)
+		case ':':
+			switch name {
+			case stateFocused, stateActive, stateDisabled:
+				c.states = append(c.states, name)
+			default:
+				return c, fmt.Errorf("unknown state %q", name)
+			}
+		}
+	}
+	return c, nil
+}
+
+// specificity ranks selectors like CSS: ids, then classes and states, then types
+func specificity(compounds []compound) int {
+	score := 0
+	for _, c := range compounds {
+		if c.id != "" {
+			score += 10000
+		}
+		score += 100 * (len(c.classes) + len(c.states))
+		if c.typeName != "" {
+			score++
+		}
+	}
+	return score
+}
+
+// styledNode is a component along with the states it's in
+type styledNode struct {
+	model  SizedModel
+	states map[string]bool
+}
+
+func (c compound) matches(node styledNode) bool {
+	if c.typeName != "" && !matchesType(c.typeName, node.model) {
+		return false
+	}
+	if c.id != "" {
+		ident, ok := node.model.(Identifiable)
+		if !ok || ident.ID() != c.id {
+			return false
+		}
+	}
+	for _, class := range c.classes {
+		classified, ok := node.model.(Classified)
+		if !ok || !containsString(classified.Classes(), class) {
+			return false
+		}
+	}
+	for _, state := range c.states {
+		if !node.states[state] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesType accepts the Go type name with or without its "Layout" suffix,
+// so "List" matches a ListLayout and "Layout" a GenericLayout
+func matchesType(selector string, model SizedModel) bool {
+	name := typeName(model)
+	switch selector {
+	case name, strings.TrimSuffix(name, "Layout"):
+		return true
+	case "Layout":
+		return name == "GenericLayout"
+	}
+	return false
+}
+
+// matches reports whether rule applies to node, nested inside ancestors
+func (r styleRule) matches(node styledNode, ancestors []styledNode) bool {
+	last := len(r.selector) - 1
+	if !r.selector[last].matches(node) {
+		return false
+	}
+
+	// Remaining compounds match ancestors, innermost first
+	next := last - 1
+	for i := len(ancestors) - 1; i >= 0 && next >= 0; i-- {
+		if r.selector[next].matches(ancestors[i]) {
+			next--
+		}
+	}
+	return next < 0
+}
+
+// cascade merges every rule in sheets that matches node, least specific
+// first; later sheets win ties
+// stateful reports whether a rule targeting a focus state matched
+func cascade(sheets []*Stylesheet, node styledNode, ancestors []styledNode) (style lipgloss.Style, stateful bool) {
+	type match struct {
+		rule  styleRule
+		order int
+	}
+
+	matched := []match{}
+	order := 0
+	for _, sheet := range sheets {
+		for _, rule := range sheet.rules {
+			order++
+			if rule.matches(node, ancestors) {
+				matched = append(matched, match{rule: rule, order: order})
+			}
+		}
+	}
+	sort.SliceStable(matched, func(a, b int) bool {
+		if matched[a].rule.specificity != matched[b].rule.specificity {
+			return matched[a].rule.specificity < matched[b].rule.specificity
+		}
+		return matched[a].order < matched[b].order
+	})
+
+	style = lipgloss.NewStyle()
+	for _, m := range matched {
+		style = overlayStyle(style, m.rule.style)
+		last := m.rule.selector[len(m.rule.selector)-1]
+		if containsString(last.states, stateFocused) || containsString(last.states, stateActive) {
+			stateful = true
+		}
+	}
+	return style, stateful
+}
+
+// overlayStyle returns base with everything top sets applied over it
+// lipgloss can't tell an explicit zero padding or margin from an unset
+// one, so only non-zero sides of top replace those of base
+func overlayStyle(base, top lipgloss.Style) lipgloss.Style {
+	style := top.Inherit(base)
+
+	pt, pr, pb, pl := top.GetPadding()
+	bt, br, bb, bl := base.GetPadding()
+	style = style.Padding(pick(pt, bt), pick(pr, br), pick(pb, bb), pick(pl, bl))
+
+	mt, mr, mb, ml := top.GetMargin()
+	bt, br, bb, bl = base.GetMargin()
+	return style.Margin(pick(mt, bt), pick(mr, br), pick(mb, bb), pick(ml, bl))
+}
+
+func pick(top, base int) int {
+	if top != 0 {
+		return top
+	}
+	return base
+}
+
+// inheritedText returns the parts of style that nested components inherit
+func inheritedText(style lipgloss.Style) lipgloss.Style {
+	text := lipgloss.NewStyle()
+	if _, none := style.GetForeground().(lipgloss.NoColor); !none {
+		text = text.Foreground(style.GetForeground())
+	}
+	if style.GetBold() {
+		text = text.Bold(true)
+	}
+	if style.GetItalic() {
+		text = text.Italic(true)
+	}
+	if style.GetFaint() {
+		text = text.Faint(true)
+	}
+	if style.GetUnderline() {
+		text = text.Underline(true)
+	}
+	return text
+}
+
+// SetStylesheet styles this layout's subtree with sheet, on top of any
+// sheet set further up the tree
+func (l *GenericLayout) SetStylesheet(sheet *Stylesheet) {
+	l.stylesheet = sheet
+}
+
+// SetStylesheet styles the whole tree with sheet
+func (r *RootLayout) SetStylesheet(sheet *Stylesheet) {
+	r.inner.SetStylesheet(sheet)
+	r.restyle()
+}
+
+// restyle recomputes every child style from the stylesheets in effect
+func (r *RootLayout) restyle() {
+	root := styledNode{model: r.inner, states: map[string]bool{}}
+	r.inner.restyle(nil, root, nil, lipgloss.NewStyle())
+	r.styledKey = r.inner.styleKey()
+}
+
+// restyleIfChanged restyles after an update that changed the tree, focus,
+// or a component's states, IDs or classes, and skips the rest like ticks
+func (r *RootLayout) restyleIfChanged() {
+	if r.inner.styleKey() != r.styledKey {
+		r.restyle()
+	}
+}
+
+// styleKey sums up everything selectors match on in l's subtree
+func (l *GenericLayout) styleKey() string {
+	var b strings.Builder
+	l.writeStyleKey(&b)
+	return b.String()
+}
+
+func (l *GenericLayout) writeStyleKey(b *strings.Builder) {
+	fmt.Fprintf(b, "%p{", l.stylesheet)
+	for i := range l.children {
+		model := l.children[i].model
+		states := l.childStates(i)
+		fmt.Fprintf(b, "%p %t%t%t", model, states[stateDisabled], states[stateFocused], states[stateActive])
+		if id, ok := model.(Identifiable); ok {
+			b.WriteString("#" + id.ID())
+		}
+		if classified, ok := model.(Classified); ok {
+			b.WriteString("." + strings.Join(classified.Classes(), "."))
+		}
+		if childLayout, ok := model.(*GenericLayout); ok {
+			childLayout.writeStyleKey(b)
+		}
+		b.WriteString(";")
+	}
+	b.WriteString("}")
+}
+
+// childStates returns the states the child at index is in
+func (l *GenericLayout) childStates(index int) map[string]bool {
+	model := l.children[index].model
+	states := map[string]bool{}
+
+	if isDisabled(model) {
+		states[stateDisabled] = true
+	}
+	if index != l.focused || !globalFocusStack.Contains(l) {
+		return states
+	}
+
+	states[stateFocused] = true
+	if childLayout, ok := model.(*GenericLayout); ok {
+		states[stateActive] = globalFocusStack.Contains(childLayout)
+	} else if activatable, ok := model.(Activatable); ok {
+		states[stateActive] = activatable.IsActive()
+	}
+	return states
+}
+
+// restyle recomputes the styles of l's children and recurses into nested layouts
+// Without a stylesheet children keep the style they were added with, plus
+// the focus policy's indicators
+func (l *GenericLayout) restyle(sheets []*Stylesheet, self styledNode, ancestors []styledNode, inherited lipgloss.Style) {
+	if l.stylesheet != nil {
+		sheets = append(sheets[:len(sheets):len(sheets)], l.stylesheet)
+	}
+	ancestors = append(ancestors[:len(ancestors):len(ancestors)], self)
+
+	relayout := false
+	for i := range l.children {
+		child := &l.children[i]
+		states := l.childStates(i)
+		node := styledNode{model: child.model, states: states}
+
+		base, focusStyle := child.inlineStyle, child.focusStyle
+		if len(sheets) > 0 {
+			// The unfocused look keeps only the states that don't come from focus
+			resting := styledNode{model: child.model, states: map[string]bool{stateDisabled: states[stateDisabled]}}
+			cascaded, _ := cascade(sheets, resting, ancestors)
+			base = overlayStyle(cascaded, child.inlineStyle).Inherit(inherited)
+			focusStyle = base
+		}
+		base = focusPolicy.resting(base, child.model)
+
+		// Entered layouts are ancestors of the leaf on the focus path
+		_, isLayout := child.model.(*GenericLayout)
+		ancestor := isLayout && states[stateActive]
+
+		current := base
+		if states[stateFocused] {
+			current = focusPolicy.apply(focusPolicy.resting(focusStyle, child.model), child.model, ancestor)
+			if len(sheets) > 0 {
+				if focused, stateful := cascade(sheets, node, ancestors); stateful {
+					current = overlayStyle(focused, child.inlineStyle).Inherit(inherited)
+				}
+			}
+		}
+		if titled, ok := child.model.(Titled); ok {
+			titled.SetTitleHighlighted(focusPolicy.highlightsTitle(states[stateFocused], ancestor))
+		}
+
+		if current.GetHorizontalFrameSize() != child.currentStyle.GetHorizontalFrameSize() ||
+			current.GetVerticalFrameSize() != child.currentStyle.GetVerticalFrameSize() {
+			relayout = true
+		}
+		child.baseStyle = base
+		child.currentStyle = current
+
+		if childLayout, ok := child.model.(*GenericLayout); ok {
+			childLayout.restyle(sheets, node, ancestors, inheritedText(child.currentStyle).Inherit(inherited))
+		}
+	}
+
+	if relayout {
+		l.layoutChildren()
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/cactircool/bitwave/bindings"
+	"github.com/cactircool/bitwave/theme"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -16,6 +17,8 @@ type TableCell struct {
 }
 
 type TableLayout struct {
+	Identity
+	DisabledState
 	headers      []TableCell
 	rows         [][]TableCell
 	width        int
@@ -29,11 +32,19 @@ type TableLayout struct {
 	scrollOffset int
 	allowAddRows bool
 
+	keymap       *bindings.Keymap
+	editKeymap   *bindings.Keymap // Used while a cell is being edited
+
+	source        *ObservableList[[]string] // Optional bound source for rows
+	sourceVersion uint64
+
 	headerStyle       lipgloss.Style
 	cellStyle         lipgloss.Style
 	selectedStyle     lipgloss.Style
 	editStyle         lipgloss.Style
 	uneditableStyle   lipgloss.Style
+
+	truncation Truncation // How cells too wide for their column are cut
 }
 
 func NewTableLayout(headers []string, editableHeaders bool) *TableLayout {
@@ -44,7 +55,7 @@ func NewTableLayout(headers []string, editableHeaders bool) *TableLayout {
 
 	colWidths := make([]int, len(headers))
 	for i, h := range headers {
-		colWidths[i] = max(len(h), 10) // Minimum width of 10
+		colWidths[i] = max(textWidth(h), 10) // Minimum width of 10
 	}
 
 	editor := textarea.New()
@@ -53,7 +64,7 @@ func NewTableLayout(headers []string, editableHeaders bool) *TableLayout {
 	editor.CharLimit = 500
 	editor.Blur()
 
-	return &TableLayout{
+	t := &TableLayout{
 		headers:         headerCells,
 		rows:            [][]TableCell{},
 		colWidths:       colWidths,
@@ -64,12 +75,26 @@ func NewTableLayout(headers []string, editableHeaders bool) *TableLayout {
 		editingCell:     [2]int{-1, -1},
 		scrollOffset:    0,
 		allowAddRows:    false,
-		headerStyle:     lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12")).Padding(0, 1),
-		cellStyle:       lipgloss.NewStyle().Padding(0, 1),
-		selectedStyle:   lipgloss.NewStyle().Background(lipgloss.Color("240")).Padding(0, 1),
-		editStyle:       lipgloss.NewStyle().Background(lipgloss.Color("17")).Foreground(lipgloss.Color("15")).Padding(0, 1),
-		uneditableStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Padding(0, 1),
+		keymap:          bindings.Table.Clone(),
+		editKeymap:      bindings.TableEdit.Clone(),
+		truncation:      DefaultTruncation,
 	}
+	t.ApplyTheme(theme.Current())
+	return t
+}
+
+// SetTruncation sets how cells too wide for their column are cut
+func (t *TableLayout) SetTruncation(truncation Truncation) {
+	t.truncation = truncation
+}
+
+// ApplyTheme rebuilds the table's styles from th
+func (t *TableLayout) ApplyTheme(th *theme.Theme) {
+	t.headerStyle = lipgloss.NewStyle().Bold(true).Foreground(th.Color(theme.Primary)).Padding(0, 1)
+	t.cellStyle = lipgloss.NewStyle().Foreground(th.Color(theme.Text)).Padding(0, 1)
+	t.selectedStyle = lipgloss.NewStyle().Background(th.Color(theme.Highlight)).Padding(0, 1)
+	t.editStyle = lipgloss.NewStyle().Background(th.Color(theme.Edit)).Foreground(th.Color(theme.EditText)).Padding(0, 1)
+	t.uneditableStyle = lipgloss.NewStyle().Foreground(th.Color(theme.Muted)).Padding(0, 1)
 }
 
 func (t *TableLayout) AddRow(cells []string, editable []bool) {
@@ -86,12 +111,127 @@ func (t *TableLayout) AddRow(cells []string, editable []bool) {
 		row[i] = TableCell{Value: c, Editable: ed}
 
 		// Update column width if needed
-		if len(c) > t.colWidths[i] {
-			t.colWidths[i] = len(c)
+		if w := textWidth(c); w > t.colWidths[i] {
+			t.colWidths[i] = w
 		}
 	}
 
 	t.rows = append(t.rows, row)
+	if t.source != nil {
+		t.source.Append(append([]string(nil), cells...))
+	}
+}
+
+// BindRows makes the table's rows track source
+// Edits, added rows and deleted rows are written back to source
+func (t *TableLayout) BindRows(source *ObservableList[[]string]) {
+	t.source = source
+	t.sourceVersion = source.Version() - 1 // Force the first sync
+	t.syncSource()
+}
+
+// syncSource rebuilds rows from the bound source if it changed
+func (t *TableLayout) syncSource() {
+	if t.source == nil {
+		return
+	}
+	values, version := t.source.snapshot()
+	if version == t.sourceVersion {
+		return
+	}
+	t.sourceVersion = version
+
+	// The edited row may have moved or gone
+	if t.editMode && t.editingCell[0] >= 0 {
+		t.cancelEdit()
+	}
+
+	// Keep the editability of rows that are still there, matched by value
+	// since rows before them may have been added or removed
+	editability := map[string][][]TableCell{}
+	for _, row := range t.rows {
+		key := rowKey(row)
+		editability[key] = append(editability[key], row)
+	}
+
+	rows := make([][]TableCell, 0, len(values))
+	for _, cells := range values {
+		var old []TableCell
+		key := strings.Join(cells, "\x00")
+		if matches := editability[key]; len(matches) > 0 {
+			old, editability[key] = matches[0], matches[1:]
+		}
+		row := make([]TableCell, len(t.headers))
+		for c := range row {
+			editable := true
+			if c < len(old) {
+				editable = old[c].Editable
+			}
+			if c < len(cells) {
+				row[c] = TableCell{Value: cells[c], Editable: editable}
+			} else {
+				row[c] = TableCell{Editable: editable}
+			}
+		}
+		rows = append(rows, row)
+	}
+	t.rows = rows
+
+	if t.selectedRow >= len(t.rows) {
+		t.selectedRow = len(t.rows) - 1
+	}
+	if t.selectedRow < 0 && len(t.rows) > 0 {
+		t.selectedRow = 0
+	}
+	t.adjustScroll()
+}
+
+// rowKey identifies a row by its values
+func rowKey(row []TableCell) string {
+	values := make([]string, len(row))
+	for c, cell := range row {
+		values[c] = cell.Value
+	}
+	return strings.Join(values, "\x00")
+}
+
+// cancelEdit leaves edit mode without saving
+func (t *TableLayout) cancelEdit() {
+	t.editMode = false
+	t.editor.Blur()
+	t.editingCell = [2]int{-1, -1}
+}
+
+// rowValues returns the cell values of row r
+func (t *TableLayout) rowValues(r int) []string {
+	values := make([]string, len(t.rows[r]))
+	for c, cell := range t.rows[r] {
+		values[c] = cell.Value
+	}
+	return values
+}
+
+// Keymap returns the table's navigation keymap, which can be overridden per table
+func (t *TableLayout) Keymap() *bindings.Keymap {
+	return t.keymap
+}
+
+// EditKeymap returns the keymap used while a cell is being edited
+func (t *TableLayout) EditKeymap() *bindings.Keymap {
+	return t.editKeymap
+}
+
+// ActiveKeymap returns the edit keymap while editing, the navigation keymap otherwise
+func (t *TableLayout) ActiveKeymap() *bindings.Keymap {
+	if t.editMode {
+		return t.editKeymap
+	}
+	return t.keymap
+}
+
+func (t *TableLayout) SetKeymaps(keymap, editKeymap *bindings.Keymap) {
+	t.keymap = keymap
+	t.editKeymap = editKeymap
 }
 
 func (t *TableLayout) SetAllowAddRows(allow bool) {
@@ -105,6 +245,9 @@ func (t *TableLayout) addNewRow() {
 		row[i] = TableCell{Value: "", Editable: true}
 	}
 	t.rows = append(t.rows, row)
+	if t.source != nil {
+		t.source.Append(t.rowValues(len(t.rows) - 1))
+	}
 
 	// Move to the new row
 	t.selectedRow = len(t.rows) - 1
@@ -148,16 +291,59 @@ func (t *TableLayout) SetSize(width, height int) {
 }
 
 func (t *TableLayout) GetFocusState() FocusState {
+	if t.IsDisabled() {
+		return Disabled
+	}
 	return Interactive
 }
 
 func (t *TableLayout) OnFocus(baseStyle lipgloss.Style) (lipgloss.Style, tea.Cmd) {
-	return baseStyle.Border(lipgloss.ThickBorder()), nil
+	// Focus is drawn by the layout's FocusPolicy
+	return baseStyle, nil
 }
 
 func (t *TableLayout) OnBlur() {
-	t.editMode = false
-	t.editor.Blur()
+	t.cancelEdit()
+}
+
+// SetDisabled cancels any cell edit when the table is switched off
+func (t *TableLayout) SetDisabled(disabled bool) {
+	t.DisabledState.SetDisabled(disabled)
+	if disabled {
+		t.cancelEdit()
+	}
+}
+
+// Describe summarizes the table for the linear view, as of the last Update
+// It's called while rendering, so it doesn't sync the bound source
+func (t *TableLayout) Describe() string {
+	if t.selectedCol < 0 || t.selectedCol >= len(t.headers) {
+		return fmt.Sprintf("Table, %d rows", len(t.rows))
+	}
+
+	column := t.headers[t.selectedCol].Value
+	var description string
+	if t.selectedRow < 0 {
+		description = fmt.Sprintf("Table, header %s", column)
+	} else if t.selectedRow < len(t.rows) {
+		description = fmt.Sprintf("Table, row %d of %d, %s: %s", t.selectedRow+1, len(t.rows), column, t.rows[t.selectedRow][t.selectedCol].Value)
+	} else {
+		return fmt.Sprintf("Table, %d rows", len(t.rows))
+	}
+	if t.editMode {
+		description += ", editing"
+	}
+	return description
+}
+
+// CapturesKey claims every key while a cell is being edited
+func (t *TableLayout) CapturesKey(key tea.KeyMsg) bool {
+	return t.editMode && !t.IsDisabled()
+}
+
+// IsActive reports whether a cell is being edited
+func (t *TableLayout) IsActive() bool {
+	return t.editMode
 }
 
 func (t *TableLayout) Init() tea.Cmd {
@@ -165,35 +351,53 @@ func (t *TableLayout) Init() tea.Cmd {
 }
 
 func (t *TableLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if key, ok := msg.(tea.KeyMsg); ok {
+	editing := t.editMode
+	t.syncSource()
+
+	if isKeyInput(msg) {
+		if editing && !t.editMode {
+			// The source changed under the edit; the key was typed for the editor
+			return t, nil
+		}
 		if t.editMode {
-			switch key.String() {
-			case bindings.CycleEscape:
-				// Cancel editing
-				t.editMode = false
-				t.editor.Blur()
-				t.editingCell = [2]int{-1, -1}
+			action, _ := t.editKeymap.ActionFor(msg)
+			switch action {
+			case bindings.Cancel:
+				t.cancelEdit()
 				return t, nil
-			case bindings.CycleEnter:
+			case bindings.Save:
 				// Save edit
 				value := strings.TrimSpace(t.editor.Value())
+				var oldValue string
 				if t.editingCell[0] == -1 {
 					// Editing header
+					oldValue = t.headers[t.editingCell[1]].Value
 					t.headers[t.editingCell[1]].Value = value
 				} else {
 					// Editing cell
+					oldValue = t.rows[t.editingCell[0]][t.editingCell[1]].Value
 					t.rows[t.editingCell[0]][t.editingCell[1]].Value = value
+					if t.source != nil {
+						t.source.SetAt(t.editingCell[0], t.rowValues(t.editingCell[0]))
+					}
+				}
+				edited := CellEditedEvent{
+					Source:   t,
+					Row:      t.editingCell[0],
+					Col:      t.editingCell[1],
+					OldValue: oldValue,
+					Value:    value,
 				}
 
 				// Update column width
-				if len(value) > t.colWidths[t.editingCell[1]] {
-					t.colWidths[t.editingCell[1]] = len(value)
+				if w := textWidth(value); w > t.colWidths[t.editingCell[1]] {
+					t.colWidths[t.editingCell[1]] = w
 				}
 
 				t.editMode = false
 				t.editor.Blur()
 				t.editingCell = [2]int{-1, -1}
-				return t, nil
+				return t, Publish(edited)
 			default:
 				// Forward to editor
 				var cmd tea.Cmd
@@ -203,39 +407,43 @@ func (t *TableLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Navigation mode
-		switch key.String() {
-		case "up", "k":
+		action, _ := t.keymap.ActionFor(msg)
+		switch action {
+		case bindings.Up:
 			if t.selectedRow > -1 {
 				t.selectedRow--
 				t.adjustScroll()
 			}
 			return t, nil
-		case "down", "j":
+		case bindings.Down:
 			if t.selectedRow < len(t.rows)-1 {
 				t.selectedRow++
 				t.adjustScroll()
 			}
 			return t, nil
-		case "left", "h":
+		case bindings.Left:
 			if t.selectedCol > 0 {
 				t.selectedCol--
 			}
 			return t, nil
-		case "right", "l":
+		case bindings.Right:
 			if t.selectedCol < len(t.headers)-1 {
 				t.selectedCol++
 			}
 			return t, nil
-		case "n":
+		case bindings.AddRow:
 			// Add new row (only if allowed)
 			if t.allowAddRows {
 				t.addNewRow()
 			}
 			return t, nil
-		case "d":
+		case bindings.DeleteRow:
 			// Delete current row (only if allowed and not editing)
 			if t.allowAddRows && t.selectedRow >= 0 && t.selectedRow < len(t.rows) {
 				t.rows = append(t.rows[:t.selectedRow], t.rows[t.selectedRow+1:]...)
+				if t.source != nil {
+					t.source.RemoveAt(t.selectedRow)
+				}
 				if t.selectedRow >= len(t.rows) && t.selectedRow > 0 {
 					t.selectedRow--
 				}
@@ -245,7 +453,7 @@ func (t *TableLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				t.adjustScroll()
 			}
 			return t, nil
-		case bindings.CycleEnter, "e", " ":
+		case bindings.Edit:
 			// Enter edit mode
 			var cell *TableCell
 			var value string
@@ -270,6 +478,14 @@ func (t *TableLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return t, nil
 		}
+		return t, nil
+	}
+
+	// Non-key messages (cursor blink etc.) still reach the editor
+	if t.editMode {
+		var cmd tea.Cmd
+		t.editor, cmd = t.editor.Update(msg)
+		return t, cmd
 	}
 
 	return t, nil
@@ -411,11 +627,11 @@ func (t *TableLayout) View() string {
 
 		if t.editMode && t.editingCell[0] == -1 && t.editingCell[1] == i {
 			// Editing a header cell
-			content := truncate(t.editor.Value(), t.colWidths[i])
-			b.WriteString(t.editStyle.Render(content))
+			content := t.truncation.Fit(t.editor.Value(), t.colWidths[i])
+			b.WriteString(t.renderCell(t.editStyle, content, cellEditing))
 		} else {
-			content := truncate(h.Value, t.colWidths[i])
-			b.WriteString(style.Render(content))
+			content := t.truncation.Fit(h.Value, t.colWidths[i])
+			b.WriteString(t.renderCell(style, content, selectedMark(t.selectedRow == -1 && t.selectedCol == i)))
 		}
 		b.WriteString("│")
 	}
@@ -447,8 +663,8 @@ func (t *TableLayout) View() string {
 
 			if t.editMode && t.editingCell[0] == r && t.editingCell[1] == c {
 				// Editing this cell
-				content := truncate(t.editor.Value(), t.colWidths[c])
-				b.WriteString(t.editStyle.Render(content))
+				content := t.truncation.Fit(t.editor.Value(), t.colWidths[c])
+				b.WriteString(t.renderCell(t.editStyle, content, cellEditing))
 			} else {
 				// Normal cell rendering
 				if !cell.Editable {
@@ -458,8 +674,8 @@ func (t *TableLayout) View() string {
 				} else {
 					style = t.cellStyle
 				}
-				content := truncate(cell.Value, t.colWidths[c])
-				b.WriteString(style.Render(content))
+				content := t.truncation.Fit(cell.Value, t.colWidths[c])
+				b.WriteString(t.renderCell(style, content, selectedMark(r == t.selectedRow && c == t.selectedCol)))
 			}
 			b.WriteString("│")
 		}
@@ -484,27 +700,48 @@ func (t *TableLayout) View() string {
 	}
 
 	// Status line
+	var helpText string
 	if t.editMode {
-		b.WriteString("\n[EDIT MODE] Enter=Save Esc=Cancel")
+		helpText = "[EDIT MODE] " + shortHelp(t.editKeymap)
 	} else {
-		helpText := fmt.Sprintf("↑↓←→=Navigate Enter/e/Space=Edit | Row %d/%d", t.selectedRow+1, len(t.rows))
+		helpText = fmt.Sprintf("%s | Row %d/%d", shortHelp(t.keymap, bindings.Up, bindings.Down, bindings.Left, bindings.Right, bindings.Edit), t.selectedRow+1, len(t.rows))
 		if t.allowAddRows {
-			helpText += " | n=New d=Delete"
+			helpText += " | " + shortHelp(t.keymap, bindings.AddRow, bindings.DeleteRow)
 		}
-		b.WriteString("\n" + helpText)
 	}
+	b.WriteString("\n" + lipgloss.NewStyle().MaxWidth(t.width).Render(helpText))
 
 	return b.String()
 }
 
-func truncate(s string, width int) string {
-	if len(s) <= width {
-		return s
+// cellMark is the state of a cell that accessible markers spell out
+type cellMark int
+
+const (
+	cellPlain cellMark = iota
+	cellSelected
+	cellEditing
+)
+
+func selectedMark(selected bool) cellMark {
+	if selected {
+		return cellSelected
 	}
-	if width < 3 {
-		return s[:width]
+	return cellPlain
+}
+
+// renderCell renders a cell, bracketing it when markers are on since the
+// selected and edited cells otherwise differ only in color
+func (t *TableLayout) renderCell(style lipgloss.Style, content string, mark cellMark) string {
+	if accessibility.Markers {
+		switch mark {
+		case cellSelected:
+			return marked(style, content, "[", "]")
+		case cellEditing:
+			return marked(style, content, "{", "}")
+		}
 	}
-	return s[:width-3] + "..."
+	return style.Render(content)
 }
 
 func min(a, b int) int {
@@ -0,0 +1,56 @@
+package layout
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTableShrinkingSourceWhileEditing(t *testing.T) {
+	tests := []struct {
+		name string
+		keep [][]string // What the source is cut down to while editing
+	}{
+		{"edited row shifts up", [][]string{{"b"}, {"c"}}},
+		{"edited row past the end", [][]string{{"c"}}},
+		{"source emptied", [][]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewObservableList([]string{"a"}, []string{"b"}, []string{"c"})
+			table := NewTableLayout([]string{"name"}, false)
+			table.SetSize(40, 10)
+			table.BindRows(source)
+
+			shrinkWhile(table, source, []string{"down", "down", "enter"}, tt.keep...)
+			press(table, "x")
+			if table.editMode {
+				t.Error("still editing after the source changed")
+			}
+
+			// Editing again works on the rows that are left
+			press(table, "enter", "enter")
+			if got := source.Items(); len(got) != len(tt.keep) || len(got) > 0 && !reflect.DeepEqual(got, tt.keep) {
+				t.Errorf("source = %q, want %q", got, tt.keep)
+			}
+			table.View()
+		})
+	}
+}
+
+func TestTableSyncKeepsEditabilityByValue(t *testing.T) {
+	source := NewObservableList[[]string]()
+	table := NewTableLayout([]string{"name"}, false)
+	table.BindRows(source)
+	table.AddRow([]string{"locked"}, []bool{false})
+	table.AddRow([]string{"open"}, nil)
+
+	source.Insert(0, []string{"new"})
+	table.Update(ObservableChangedMsg{})
+
+	want := []bool{true, false, true}
+	for r, row := range table.rows {
+		if row[0].Editable != want[r] {
+			t.Errorf("row %d (%s) editable = %v, want %v", r, row[0].Value, row[0].Editable, want[r])
+		}
+	}
+}
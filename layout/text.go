@@ -1,31 +1,165 @@
 package layout
 
 import (
+	"strings"
+
+	"github.com/cactircool/bitwave/bindings"
+	"github.com/cactircool/bitwave/theme"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// WrapMode is how text wider than its box is broken into lines
+type WrapMode int
+
+const (
+	// WrapWord breaks lines between words, and inside words longer than a line
+	WrapWord WrapMode = iota
+	// WrapChar breaks lines at the box edge, wherever that falls
+	WrapChar
+	// WrapNone keeps lines as they are; Overflow decides what happens to the rest
+	WrapNone
+)
+
+// Overflow is what happens to text that doesn't fit its box
+type Overflow int
+
+const (
+	// OverflowClip cuts the text off at the box edge
+	OverflowClip Overflow = iota
+	// OverflowEllipsis cuts the text off and marks where with an ellipsis
+	OverflowEllipsis
+	// OverflowScroll makes the text focusable and scrollable with keys
+	OverflowScroll
 )
 
 type TextLayout struct {
-	text   string
-	width  int
-	height int
+	Identity
+	DisabledState
+	text      string
+	source    *Observable[string]               // Optional bound source for text
+	styleFunc func(*theme.Theme) lipgloss.Style // Optional themed style
+	width     int
+	height    int
+
+	markup     bool // Whether the text is parsed as markup
+	wrap       WrapMode
+	overflow   Overflow
+	truncation Truncation // Ellipsis for OverflowEllipsis
+	hAlign     lipgloss.Position
+	vAlign     lipgloss.Position
+
+	// Scroll position with OverflowScroll
+	offsetY int
+	offsetX int // Only used with WrapNone
+
+	keymap *bindings.Keymap
+}
+
+// SetTextMsg replaces the text of the TextLayout it's sent to
+type SetTextMsg struct {
+	Text string
 }
 
 func NewTextView(text string) *TextLayout {
-	return &TextLayout{text: text}
+	return &TextLayout{
+		text:       text,
+		markup:     true,
+		truncation: DefaultTruncation,
+		hAlign:     lipgloss.Left,
+		vAlign:     lipgloss.Top,
+		keymap:     bindings.Text.Clone(),
+	}
+}
+
+// SetStyleFunc styles the text from the current theme, so it follows theme changes
+func (t *TextLayout) SetStyleFunc(styleFunc func(*theme.Theme) lipgloss.Style) {
+	t.styleFunc = styleFunc
+}
+
+// Bind makes the text track source
+func (t *TextLayout) Bind(source *Observable[string]) {
+	t.source = source
+}
+
+// Text returns the current text
+func (t *TextLayout) Text() string {
+	if t.source != nil {
+		return t.source.Get()
+	}
+	return t.text
+}
+
+// SetText replaces the text, writing through to the bound source if there is one
+// The scroll position is kept where possible
+func (t *TextLayout) SetText(text string) {
+	if t.source != nil {
+		t.source.Set(text)
+	} else {
+		t.text = text
+	}
+	t.clampScroll(t.lines())
+}
+
+// SetMarkup turns markup parsing on or off; it's on by default
+// See markup.go for the tags
+func (t *TextLayout) SetMarkup(markup bool) {
+	t.markup = markup
+}
+
+func (t *TextLayout) SetWrap(wrap WrapMode) {
+	t.wrap = wrap
+	t.offsetX = 0
+}
+
+func (t *TextLayout) SetOverflow(overflow Overflow) {
+	t.overflow = overflow
+	t.offsetX, t.offsetY = 0, 0
+}
+
+// SetTruncation sets the ellipsis used by OverflowEllipsis
+func (t *TextLayout) SetTruncation(truncation Truncation) {
+	t.truncation = truncation
+}
+
+// SetAlign positions the text inside its box
+func (t *TextLayout) SetAlign(horizontal, vertical lipgloss.Position) {
+	t.hAlign = horizontal
+	t.vAlign = vertical
+}
+
+// Keymap returns the scroll keymap, which can be overridden per text
+func (t *TextLayout) Keymap() *bindings.Keymap {
+	return t.keymap
+}
+
+func (t *TextLayout) ActiveKeymap() *bindings.Keymap {
+	return t.keymap
+}
+
+func (t *TextLayout) SetKeymap(keymap *bindings.Keymap) {
+	t.keymap = keymap
 }
 
 func (t *TextLayout) SetSize(width, height int) {
 	t.width = width
 	t.height = height
+	t.clampScroll(t.lines())
 }
 
 func (t *TextLayout) GetFocusState() FocusState {
-	return NotFocusable
+	if t.overflow != OverflowScroll {
+		return NotFocusable
+	}
+	if t.IsDisabled() {
+		return Disabled
+	}
+	return Focusable
 }
 
 func (t *TextLayout) OnFocus(baseStyle lipgloss.Style) (lipgloss.Style, tea.Cmd) {
-	// Text doesn't show focus
+	// Only scrollable text takes focus, drawn by the layout's FocusPolicy
 	return baseStyle, nil
 }
 
@@ -36,12 +170,138 @@ func (t *TextLayout) Init() tea.Cmd {
 }
 
 func (t *TextLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if set, ok := msg.(SetTextMsg); ok {
+		t.SetText(set.Text)
+		return t, nil
+	}
+	if t.overflow != OverflowScroll || !isKeyInput(msg) {
+		return t, nil
+	}
+
+	lines := t.lines()
+	page := max(t.innerHeight(), 1)
+	action, _ := t.keymap.ActionFor(msg)
+	switch action {
+	case bindings.Up:
+		t.offsetY--
+	case bindings.Down:
+		t.offsetY++
+	case bindings.PageUp:
+		t.offsetY -= page
+	case bindings.PageDown:
+		t.offsetY += page
+	case bindings.Top:
+		t.offsetY = 0
+	case bindings.Bottom:
+		t.offsetY = len(lines)
+	case bindings.Left:
+		t.offsetX--
+	case bindings.Right:
+		t.offsetX++
+	}
+	t.clampScroll(lines)
 	return t, nil
 }
 
+// style returns the box style the text is rendered with
+func (t *TextLayout) style() lipgloss.Style {
+	style := lipgloss.NewStyle()
+	if t.styleFunc != nil {
+		style = t.styleFunc(theme.Current())
+	}
+	return style
+}
+
+// innerWidth and innerHeight are the room left for text inside the style's frame
+func (t *TextLayout) innerWidth() int {
+	return max(t.width-t.style().GetHorizontalFrameSize(), 0)
+}
+
+func (t *TextLayout) innerHeight() int {
+	return max(t.height-t.style().GetVerticalFrameSize(), 0)
+}
+
+// lines returns the text broken into lines for the current width
+func (t *TextLayout) lines() []string {
+	text := t.Text()
+	if t.markup {
+		text = renderMarkup(text, theme.Current())
+	}
+	width := t.innerWidth()
+	if width > 0 {
+		switch t.wrap {
+		case WrapWord:
+			text = ansi.Wrap(text, width, "")
+		case WrapChar:
+			text = ansi.Hardwrap(text, width, true)
+		}
+	}
+	return strings.Split(text, "\n")
+}
+
+// widest returns the width of the widest line
+func widest(lines []string) int {
+	w := 0
+	for _, line := range lines {
+		w = max(w, textWidth(line))
+	}
+	return w
+}
+
+// scroll returns the offsets kept inside lines, which may have changed
+// since they were last clamped, e.g. when a bound source shrinks
+func (t *TextLayout) scroll(lines []string) (offsetY, offsetX int) {
+	offsetY = max(min(t.offsetY, len(lines)-t.innerHeight()), 0)
+	if t.wrap == WrapNone {
+		offsetX = max(min(t.offsetX, widest(lines)-t.innerWidth()), 0)
+	}
+	return offsetY, offsetX
+}
+
+func (t *TextLayout) clampScroll(lines []string) {
+	t.offsetY, t.offsetX = t.scroll(lines)
+}
+
 func (t *TextLayout) View() string {
-	style := lipgloss.NewStyle().
-		Width(t.width).
-		Height(t.height)
-	return style.Render(t.text)
+	width, height := t.innerWidth(), t.innerHeight()
+	lines := t.lines()
+	offsetY, offsetX := t.scroll(lines)
+
+	// Vertical overflow
+	if len(lines) > height {
+		switch t.overflow {
+		case OverflowScroll:
+			lines = lines[offsetY:min(offsetY+height, len(lines))]
+		case OverflowEllipsis:
+			lines = lines[:height]
+			if height > 0 {
+				last := lines[height-1]
+				ellipsis := t.truncation.Ellipsis
+				lines[height-1] = ansi.Truncate(last, max(width-textWidth(ellipsis), 0), "") + ellipsis
+			}
+		default:
+			lines = lines[:height]
+		}
+	}
+
+	// Horizontal overflow, only possible without wrapping
+	for i, line := range lines {
+		switch {
+		case t.overflow == OverflowScroll:
+			lines[i] = ansi.Truncate(ansi.TruncateLeft(line, offsetX, ""), width, "")
+		case t.overflow == OverflowEllipsis:
+			lines[i] = t.truncation.Truncate(line, width)
+		default:
+			lines[i] = ansi.Truncate(line, width, "")
+		}
+	}
+
+	// Width and Height include padding but not the border or margins
+	style := t.style()
+	return style.
+		Width(t.width - style.GetHorizontalBorderSize() - style.GetHorizontalMargins()).
+		Height(t.height - style.GetVerticalBorderSize() - style.GetVerticalMargins()).
+		Align(t.hAlign).
+		AlignVertical(t.vAlign).
+		Render(strings.Join(lines, "\n"))
 }
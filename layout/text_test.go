@@ -0,0 +1,60 @@
+package layout
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func numberedLines(n int) string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = strings.Repeat("x", i%7+1)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestTextLayoutScrollClamp(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   int
+		after    int
+		wrap     WrapMode
+		overflow Overflow
+		width    int
+		height   int
+	}{
+		{"source shrinks below offset", 100, 10, WrapWord, OverflowScroll, 20, 5},
+		{"source shrinks to fewer lines than height", 100, 2, WrapWord, OverflowScroll, 20, 5},
+		{"source emptied", 100, 0, WrapWord, OverflowScroll, 20, 5},
+		{"unwrapped source shrinks", 100, 10, WrapNone, OverflowScroll, 3, 5},
+		{"ellipsis narrower than its mark", 100, 50, WrapNone, OverflowEllipsis, 0, 5},
+		{"zero size", 100, 10, WrapWord, OverflowScroll, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewObservable(numberedLines(tt.before))
+			text := NewTextView("")
+			text.SetMarkup(false)
+			text.SetWrap(tt.wrap)
+			text.SetOverflow(tt.overflow)
+			text.Bind(source)
+			text.SetSize(tt.width, tt.height)
+
+			text.Update(tea.KeyMsg{Type: tea.KeyEnd})
+			text.Update(tea.KeyMsg{Type: tea.KeyRight})
+			source.Set(numberedLines(tt.after))
+			text.Update(ObservableChangedMsg{})
+
+			view := text.View()
+			if got := strings.Count(view, "\n") + 1; tt.height > 0 && got != tt.height {
+				t.Errorf("view has %d lines, want %d", got, tt.height)
+			}
+
+			// Scrolling still works from the clamped position
+			text.Update(tea.KeyMsg{Type: tea.KeyUp})
+			text.View()
+		})
+	}
+}
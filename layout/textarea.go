@@ -1,6 +1,8 @@
 package layout
 
 import (
+	"fmt"
+
 	"github.com/cactircool/bitwave/bindings"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
@@ -8,16 +10,22 @@ import (
 )
 
 type TextareaLayout struct {
+	Identity
+	DisabledState
 	textarea textarea.Model
 	width    int
 	height   int
-	isActive bool // Whether we're actively editing (entered)
+	isActive bool   // Whether we're actively editing (entered)
+	tab      string // Inserted when Tab is pressed while editing
+	keymap   *bindings.Keymap
 }
 
 func NewTextareaLayout(ta textarea.Model) *TextareaLayout {
 	return &TextareaLayout{
 		textarea: ta,
 		isActive: false,
+		tab:      "    ",
+		keymap:   bindings.Textarea.Clone(),
 	}
 }
 
@@ -28,6 +36,24 @@ func DefaultTextareaLayout() *TextareaLayout {
 	return NewTextareaLayout(ta)
 }
 
+// Keymap returns the textarea's keymap, which can be overridden per textarea
+func (t *TextareaLayout) Keymap() *bindings.Keymap {
+	return t.keymap
+}
+
+func (t *TextareaLayout) ActiveKeymap() *bindings.Keymap {
+	return t.keymap
+}
+
+func (t *TextareaLayout) SetKeymap(keymap *bindings.Keymap) {
+	t.keymap = keymap
+}
+
+// SetTabString sets what Tab inserts while editing
+func (t *TextareaLayout) SetTabString(tab string) {
+	t.tab = tab
+}
+
 func (t *TextareaLayout) SetSize(width, height int) {
 	t.width = width
 	t.height = height
@@ -36,12 +62,15 @@ func (t *TextareaLayout) SetSize(width, height int) {
 }
 
 func (t *TextareaLayout) GetFocusState() FocusState {
+	if t.IsDisabled() {
+		return Disabled
+	}
 	return Interactive
 }
 
 func (t *TextareaLayout) OnFocus(baseStyle lipgloss.Style) (lipgloss.Style, tea.Cmd) {
-	// When focused (highlighted), show thick border but don't activate editing yet
-	return baseStyle.Border(lipgloss.ThickBorder()), nil
+	// Highlighted by the layout's FocusPolicy, but don't activate editing yet
+	return baseStyle, nil
 }
 
 func (t *TextareaLayout) OnBlur() {
@@ -50,15 +79,43 @@ func (t *TextareaLayout) OnBlur() {
 	t.textarea.Blur()
 }
 
+// SetDisabled stops editing when the textarea is switched off
+func (t *TextareaLayout) SetDisabled(disabled bool) {
+	t.DisabledState.SetDisabled(disabled)
+	if disabled {
+		t.OnBlur()
+	}
+}
+
+// Describe summarizes the textarea for the linear view
+func (t *TextareaLayout) Describe() string {
+	description := fmt.Sprintf("Text area, %d lines", t.textarea.LineCount())
+	if t.isActive {
+		description += ", editing"
+	}
+	return description
+}
+
+// CapturesKey claims every key while editing, so Tab and Esc reach the textarea
+func (t *TextareaLayout) CapturesKey(key tea.KeyMsg) bool {
+	return t.isActive && !t.IsDisabled()
+}
+
+// IsActive reports whether the textarea is being edited
+func (t *TextareaLayout) IsActive() bool {
+	return t.isActive
+}
+
 func (t *TextareaLayout) Init() tea.Cmd {
 	return textarea.Blink
 }
 
 func (t *TextareaLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle enter/escape for activating/deactivating editing
-	if key, ok := msg.(tea.KeyMsg); ok {
-		switch key.String() {
-		case bindings.CycleEnter:
+	if isKeyInput(msg) {
+		action, _ := t.keymap.ActionFor(msg)
+		switch action {
+		case bindings.Activate:
 			if !t.isActive {
 				// Activate editing mode
 				t.isActive = true
@@ -66,7 +123,18 @@ func (t *TextareaLayout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return t, t.textarea.Focus()
 			}
 
-		case bindings.CycleEscape:
+		case bindings.Submit:
+			if t.isActive {
+				return t, Publish(TextSubmittedEvent{Source: t, Text: t.textarea.Value()})
+			}
+
+		case bindings.Indent:
+			if t.isActive {
+				t.textarea.InsertString(t.tab)
+				return t, nil
+			}
+
+		case bindings.Cancel:
 			if t.isActive {
 				// Deactivate editing mode
 				t.isActive = false
@@ -0,0 +1,46 @@
+package layout
+
+import (
+	"github.com/cactircool/bitwave/theme"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Themeable is implemented by models whose styles come from the theme
+type Themeable interface {
+	ApplyTheme(t *theme.Theme)
+}
+
+// ThemeChangedMsg is broadcast after the theme is switched at runtime
+type ThemeChangedMsg struct {
+	Theme *theme.Theme
+}
+
+// SetTheme switches the active theme and restyles the whole tree
+func (r *RootLayout) SetTheme(t *theme.Theme) tea.Cmd {
+	theme.Set(t)
+	walk(r.inner, func(model SizedModel) {
+		if themeable, ok := model.(Themeable); ok {
+			themeable.ApplyTheme(t)
+		}
+	})
+	r.restyle()
+
+	return func() tea.Msg {
+		return ThemeChangedMsg{Theme: t}
+	}
+}
+
+// themeCommands returns a palette command for every registered theme
+func (r *RootLayout) themeCommands() []Command {
+	commands := []Command{}
+	for _, name := range theme.Names() {
+		t, _ := theme.Lookup(name)
+		commands = append(commands, Command{
+			Title: "Theme: " + name,
+			Run: func() tea.Cmd {
+				return r.SetTheme(t)
+			},
+		})
+	}
+	return commands
+}
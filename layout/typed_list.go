@@ -0,0 +1,113 @@
+package layout
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TypedListLayout is a ListLayout whose items carry a T, so callers get
+// their values back without type assertions
+// Each value is shown as label(value); everything else, from filtering to
+// delegates, works as it does on ListLayout
+// Messages and events carry the embedded ListLayout as their Source, so
+// compare them against l.ListLayout, or match on the list's ID
+type TypedListLayout[T any] struct {
+	*ListLayout
+	label func(T) string
+}
+
+func NewTypedListLayout[T any](title string, maxSelections int, label func(T) string) *TypedListLayout[T] {
+	return &TypedListLayout[T]{
+		ListLayout: NewListLayout(title, maxSelections),
+		label:      label,
+	}
+}
+
+// Add appends values to the list
+func (l *TypedListLayout[T]) Add(values ...T) {
+	for _, value := range values {
+		l.AddItem(l.label(value), value)
+	}
+}
+
+// SetValues replaces the list's values, clearing selections
+func (l *TypedListLayout[T]) SetValues(values []T) {
+	l.itemsReplaced()
+	l.items = make([]ListItem, len(values))
+	for i, value := range values {
+		l.items[i] = ListItem{Value: l.label(value), Data: value}
+	}
+	l.writeSource()
+	l.cursor = max(min(l.cursor, len(l.items)-1), 0)
+	l.adjustScroll()
+}
+
+// SetNew makes the value of items that arrive as bare labels, from a source
+// bound with BindItems or typed into an editable list
+// Without it those items carry no T and are left out of Values, Selected and Of
+func (l *TypedListLayout[T]) SetNew(fn func(label string) T) {
+	l.newData = func(label string) interface{} {
+		return fn(label)
+	}
+}
+
+// value returns the payload of item
+// Items added through the embedded ListLayout may not carry a T, see SetNew
+func (l *TypedListLayout[T]) value(item ListItem) (T, bool) {
+	value, ok := item.Data.(T)
+	return value, ok
+}
+
+// Values returns every value in the list, in order
+func (l *TypedListLayout[T]) Values() []T {
+	l.syncSource()
+	values := make([]T, 0, len(l.items))
+	for _, item := range l.items {
+		if value, ok := l.value(item); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Selected returns the selected values, in list order
+func (l *TypedListLayout[T]) Selected() []T {
+	return l.Of(l.GetSelectedItems())
+}
+
+// Current returns the value under the cursor
+func (l *TypedListLayout[T]) Current() (T, bool) {
+	l.syncSource()
+	if l.cursor < 0 || l.cursor >= len(l.items) {
+		var zero T
+		return zero, false
+	}
+	return l.value(l.items[l.cursor])
+}
+
+// Of returns the values of items, e.g. those in a SelectionChangedEvent
+func (l *TypedListLayout[T]) Of(items []ListItem) []T {
+	values := make([]T, 0, len(items))
+	for _, item := range items {
+		if value, ok := l.value(item); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// SetOnActivate runs fn with the value of an item activated with Enter
+func (l *TypedListLayout[T]) SetOnActivate(fn func(value T) tea.Cmd) {
+	l.ListLayout.SetOnActivate(func(item ListItem) tea.Cmd {
+		if value, ok := l.value(item); ok {
+			return fn(value)
+		}
+		return nil
+	})
+}
+
+// Update returns the TypedListLayout rather than the embedded ListLayout,
+// so the layout tree keeps holding it
+func (l *TypedListLayout[T]) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	_, cmd := l.ListLayout.Update(msg)
+	return l, cmd
+}
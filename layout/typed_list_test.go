@@ -0,0 +1,61 @@
+package layout
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type typedTask struct {
+	Name string
+}
+
+func newTypedTasks() *TypedListLayout[typedTask] {
+	l := NewTypedListLayout("", 0, func(task typedTask) string { return task.Name })
+	l.SetSize(20, 10)
+	return l
+}
+
+func TestTypedListNewValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		setNew bool
+		want   []typedTask
+	}{
+		{"without SetNew", false, []typedTask{{"a"}}},
+		{"with SetNew", true, []typedTask{{"a"}, {"B"}, {"C"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewObservableList[string]()
+			l := newTypedTasks()
+			if tt.setNew {
+				l.SetNew(func(label string) typedTask { return typedTask{strings.ToUpper(label)} })
+			}
+			l.BindItems(source)
+			l.SetEditable(true)
+
+			l.Add(typedTask{"a"})
+			source.Append("b")
+			press(l, "G", "o", "c", "enter")
+
+			if got := l.Values(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Values = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypedListSetValuesWritesThrough(t *testing.T) {
+	source := NewObservableList("old")
+	l := newTypedTasks()
+	l.BindItems(source)
+	l.SetValues([]typedTask{{"x"}, {"y"}})
+
+	if got := source.Items(); !reflect.DeepEqual(got, []string{"x", "y"}) {
+		t.Errorf("source = %q, want [x y]", got)
+	}
+	if got := l.Values(); !reflect.DeepEqual(got, []typedTask{{"x"}, {"y"}}) {
+		t.Errorf("Values = %v, want [{x} {y}]", got)
+	}
+}
@@ -0,0 +1,88 @@
+package layout
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// TruncatePosition is where text that doesn't fit gets cut
+type TruncatePosition int
+
+const (
+	// TruncateEnd keeps the start of the text, "Hello w…"
+	TruncateEnd TruncatePosition = iota
+	// TruncateMiddle keeps both ends, "Hell…rld"
+	TruncateMiddle
+	// TruncateStart keeps the end of the text, "…o world"
+	TruncateStart
+)
+
+// Truncation decides how widgets shorten text to fit a width
+// Widths are display cells, so wide characters like CJK and emoji count
+// as two, and text is only ever cut between grapheme clusters
+type Truncation struct {
+	Position TruncatePosition
+	Ellipsis string // Marks where text was cut; may be empty
+}
+
+// DefaultTruncation cuts the end of text and marks it with "…"
+var DefaultTruncation = Truncation{Position: TruncateEnd, Ellipsis: "…"}
+
+// textWidth returns the display width of s, ignoring ANSI styling
+func textWidth(s string) int {
+	return ansi.StringWidth(s)
+}
+
+// Truncate shortens s to at most width cells
+func (t Truncation) Truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	total := textWidth(s)
+	if total <= width {
+		return s
+	}
+
+	ellipsis := t.Ellipsis
+	if textWidth(ellipsis) >= width {
+		// No room to show anything but the cut
+		ellipsis = ""
+	}
+	keep := width - textWidth(ellipsis)
+
+	switch t.Position {
+	case TruncateStart:
+		return ellipsis + tail(s, keep)
+	case TruncateMiddle:
+		left := (keep + 1) / 2
+		return ansi.Truncate(s, left, "") + ellipsis + tail(s, keep-left)
+	}
+	return ansi.Truncate(s, width, ellipsis)
+}
+
+// tail returns the end of s that fits in width cells
+// Dropping cells from the left can leave half of a wide character, so
+// keep dropping until the rest fits
+func tail(s string, width int) string {
+	total := textWidth(s)
+	for drop := total - width; drop <= total; drop++ {
+		if rest := ansi.TruncateLeft(s, drop, ""); textWidth(rest) <= width {
+			return rest
+		}
+	}
+	return ""
+}
+
+// Fit truncates s to width cells and pads it with spaces to exactly width
+func (t Truncation) Fit(s string, width int) string {
+	return padRight(t.Truncate(s, width), width)
+}
+
+// padRight pads s with spaces to width cells
+func padRight(s string, width int) string {
+	if gap := width - textWidth(s); gap > 0 {
+		return s + strings.Repeat(" ", gap)
+	}
+	return s
+}
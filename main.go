@@ -94,15 +94,50 @@
 package main
 
 import (
+	"flag"
 	"log"
+	"os"
 
 	"github.com/cactircool/bitwave/app"
+	"github.com/cactircool/bitwave/bindings"
+	"github.com/cactircool/bitwave/layout"
+	"github.com/cactircool/bitwave/theme"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
+	linear := flag.Bool("linear", false, "render a plain-text outline for screen readers")
+	highContrast := flag.Bool("high-contrast", false, "use the high-contrast theme")
+	flag.Parse()
+
+	// Key overrides have to be in place before components clone their keymaps
+	if path, err := bindings.DefaultConfigPath(); err == nil {
+		if err := bindings.LoadOverrides(path); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Same for the theme, which components read when they're constructed
+	if path, err := theme.DefaultConfigPath(); err == nil {
+		if _, err := os.Stat(path); err == nil {
+			t, err := theme.Load(path)
+			if err != nil {
+				log.Fatal(err)
+			}
+			theme.Set(t)
+		}
+	}
+
+	if *highContrast {
+		theme.Set(theme.HighContrast)
+	}
+
 	root := app.ConstructRoot()
+	access := layout.DetectAccessibility()
+	access.Linear = *linear
+	root.SetAccessibility(access)
 	p := tea.NewProgram(root, tea.WithAltScreen())
+	root.AttachProgram(p)
 	if _, err := p.Run(); err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,51 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// file is the on-disk format of a theme, e.g.
+// {"name": "mine", "colors": {"primary": "#89b4fa", "muted": "8"}}
+type file struct {
+	Name   string          `json:"name"`
+	Colors map[Role]string `json:"colors"`
+}
+
+// DefaultConfigPath is where a user theme is read from at startup
+func DefaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bitwave", "theme.json"), nil
+}
+
+// Load reads a theme from a JSON file and registers it
+// Roles the file leaves out fall back to Default; unknown roles are an error,
+// so typos don't go unnoticed
+func Load(path string) (*Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for role := range f.Colors {
+		if !role.Valid() {
+			return nil, fmt.Errorf("%s: unknown role %q", path, role)
+		}
+	}
+	if f.Name == "" {
+		f.Name = filepath.Base(path)
+	}
+
+	t := New(f.Name, f.Colors)
+	Register(t)
+	return t, nil
+}
@@ -0,0 +1,187 @@
+package theme
+
+import (
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Role names what a color is used for, rather than the color itself
+type Role string
+
+const (
+	Text        Role = "text"         // Default foreground
+	Inverse     Role = "inverse"      // Text drawn on a colored background
+	Primary     Role = "primary"      // Headers and accents
+	Title       Role = "title"        // Titles and app branding
+	Muted       Role = "muted"        // Help text, disabled and secondary content
+	Selection   Role = "selection"    // Cursor background
+	Selected    Role = "selected"     // Items the user has picked
+	Highlight   Role = "highlight"    // Selected cell background
+	Edit        Role = "edit"         // Background of a field being edited
+	EditText    Role = "edit_text"    // Text of a field being edited
+	FocusBorder Role = "focus_border" // Border of the focused component
+	FocusPath   Role = "focus_path"   // Layouts the focused component is nested in
+	Error       Role = "error"
+	Warning     Role = "warning"
+)
+
+var roles = []Role{
+	Text, Inverse, Primary, Title, Muted, Selection, Selected, Highlight,
+	Edit, EditText, FocusBorder, FocusPath, Error, Warning,
+}
+
+// Valid reports whether r is one of the roles above
+func (r Role) Valid() bool {
+	for _, role := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Theme maps roles to colors
+// Colors are anything lipgloss.Color accepts: ANSI numbers or hex strings
+type Theme struct {
+	Name   string
+	colors map[Role]string
+}
+
+// New creates a theme; roles missing from colors fall back to Default
+func New(name string, colors map[Role]string) *Theme {
+	merged := map[Role]string{}
+	for role, color := range Default.colors {
+		merged[role] = color
+	}
+	for role, color := range colors {
+		merged[role] = color
+	}
+	return &Theme{Name: name, colors: merged}
+}
+
+// Color returns the color for role, or no color if the role is unset
+func (t *Theme) Color(role Role) lipgloss.TerminalColor {
+	if color := t.colors[role]; color != "" {
+		return lipgloss.Color(color)
+	}
+	return lipgloss.NoColor{}
+}
+
+// Has reports whether the theme sets a color for role
+func (t *Theme) Has(role Role) bool {
+	return t.colors[role] != ""
+}
+
+// Shipped themes
+var (
+	Default = &Theme{Name: "default", colors: map[Role]string{
+		Inverse:   "0",
+		Primary:   "12",
+		Title:     "13",
+		Muted:     "240",
+		Selection: "12",
+		Selected:  "10",
+		Highlight: "240",
+		Edit:      "17",
+		EditText:  "15",
+		Error:     "9",
+		Warning:   "11",
+	}}
+
+	Dark = New("dark", map[Role]string{
+		Text:        "#e0def4",
+		Inverse:     "#191724",
+		Primary:     "#9ccfd8",
+		Title:       "#c4a7e7",
+		Muted:       "#6e6a86",
+		Selection:   "#9ccfd8",
+		Selected:    "#31748f",
+		Highlight:   "#403d52",
+		Edit:        "#26233a",
+		EditText:    "#e0def4",
+		FocusBorder: "#ebbcba",
+		FocusPath:   "#908caa",
+		Error:       "#eb6f92",
+		Warning:     "#f6c177",
+	})
+
+	Light = New("light", map[Role]string{
+		Text:        "#575279",
+		Inverse:     "#faf4ed",
+		Primary:     "#286983",
+		Title:       "#907aa9",
+		Muted:       "#9893a5",
+		Selection:   "#286983",
+		Selected:    "#56949f",
+		Highlight:   "#dfdad9",
+		Edit:        "#f2e9e1",
+		EditText:    "#575279",
+		FocusBorder: "#d7827e",
+		FocusPath:   "#797593",
+		Error:       "#b4637a",
+		Warning:     "#ea9d34",
+	})
+
+	// HighContrast sticks to the 16 basic ANSI colors, which terminals
+	// render with the strongest contrast and low-color profiles support
+	HighContrast = New("high-contrast", map[Role]string{
+		Text:        "15",
+		Inverse:     "0",
+		Primary:     "14",
+		Title:       "11",
+		Muted:       "7",
+		Selection:   "11",
+		Selected:    "10",
+		Highlight:   "4",
+		Edit:        "5",
+		EditText:    "15",
+		FocusBorder: "11",
+		FocusPath:   "15",
+		Error:       "9",
+		Warning:     "11",
+	})
+)
+
+var (
+	current = Default
+	themes  = map[string]*Theme{}
+)
+
+func init() {
+	for _, t := range []*Theme{Default, Dark, Light, HighContrast} {
+		Register(t)
+	}
+}
+
+// Current returns the active theme
+func Current() *Theme {
+	return current
+}
+
+// Set makes t the active theme
+// Use RootLayout.SetTheme at runtime so the layout tree is restyled
+func Set(t *Theme) {
+	current = t
+}
+
+// Register makes a theme available by name
+func Register(t *Theme) {
+	themes[t.Name] = t
+}
+
+// Lookup returns the registered theme with the given name
+func Lookup(name string) (*Theme, bool) {
+	t, ok := themes[name]
+	return t, ok
+}
+
+// Names returns the names of every registered theme, sorted
+func Names() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}